@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var addEditFlag bool
+
+var addCmd = &cobra.Command{
+	Use:   "add [content]",
+	Short: "Add a tip you wrote yourself",
+	Long: `Add a tip you wrote yourself to a topic.
+
+  tips add -t git "git switch - goes back to the previous branch"
+  tips add -t git --tag branches --edit
+  some-command | tips add -t git -
+
+With content arguments, they are added as one tip. With --edit, or with no
+arguments at a terminal, $VISUAL or $EDITOR opens a template to write the tip
+in. With '-', or with no arguments and piped input, every non-empty line of
+standard input is added as a tip, skipping lines starting with '#'.
+
+The topic comes from -t only, never from the default topics in the config
+file or profile. Tips are tagged with --tag. A tip with the same topic and
+content as an existing one, ignoring case and whitespace, is skipped as a
+duplicate.`,
+	Run: addTips,
+}
+
+// editFile opens path in the user's editor and waits for it to exit. It is a
+// variable so tests can stand in for the editor.
+var editFile = func(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	// Editors are often configured with arguments, like "code --wait".
+	fields := strings.Fields(editor)
+	cmd := exec.Command(fields[0], append(fields[1:], path)...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to run editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+// editInTempFile writes contents to a temporary file, opens it in the editor
+// and returns what was saved.
+func editInTempFile(pattern, contents string) (string, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(contents)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := editFile(f.Name()); err != nil {
+		return "", err
+	}
+
+	edited, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temporary file: %w", err)
+	}
+	return string(edited), nil
+}
+
+// A tipTemplate is a tip as written in the editor: "topic:" and "tags:"
+// headers, a blank line, then the content. Lines starting with
+// tipTemplateComment are help; any other line, including one starting with
+// '#', is kept as content.
+type tipTemplate struct {
+	topic   string
+	tags    []string
+	content string
+}
+
+const tipTemplateComment = "# tips:"
+
+const tipTemplateHelp = tipTemplateComment + ` Lines starting with "` + tipTemplateComment + `" are ignored. Tags are separated by commas.
+` + tipTemplateComment + ` Leave the content empty to cancel.
+`
+
+func (t tipTemplate) String() string {
+	return fmt.Sprintf("topic: %s\ntags: %s\n\n%s\n%s", t.topic, strings.Join(t.tags, ", "), t.content, tipTemplateHelp)
+}
+
+func parseTipTemplate(text string) tipTemplate {
+	var t tipTemplate
+	var content []string
+	inHeader := true
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(line, tipTemplateComment) {
+			continue
+		}
+		if inHeader {
+			key, value, found := strings.Cut(line, ":")
+			switch strings.ToLower(strings.TrimSpace(key)) {
+			case "topic":
+				if found {
+					t.topic = strings.TrimSpace(value)
+					continue
+				}
+			case "tags":
+				if found {
+					t.tags = normalizeTags(strings.Split(value, ","))
+					continue
+				}
+			case "":
+				if !found {
+					inHeader = false
+					continue
+				}
+			}
+			inHeader = false
+		}
+		content = append(content, line)
+	}
+	t.content = strings.TrimSpace(strings.Join(content, "\n"))
+	return t
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func addTips(cmd *cobra.Command, args []string) {
+	fromStdin := (len(args) == 1 && args[0] == "-") || (len(args) == 0 && !addEditFlag && !isTerminal(os.Stdin))
+	if addEditFlag && len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: --edit can't be combined with content arguments\n")
+		os.Exit(1)
+	}
+	topics := explicitTopics(cmd)
+	if len(topics) > 1 || len(topics) == 0 && (fromStdin || len(args) > 0) {
+		fmt.Fprintf(os.Stderr, "Error: Please specify exactly one topic using -t or --topic\n")
+		os.Exit(1)
+	}
+
+	var templates []tipTemplate
+	switch {
+	case fromStdin:
+		lines, err := readTipLines(os.Stdin)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading tips: %v\n", err)
+			os.Exit(1)
+		}
+		for _, line := range lines {
+			templates = append(templates, tipTemplate{topic: topics[0], tags: tagFlag, content: line})
+		}
+	case len(args) > 0:
+		templates = append(templates, tipTemplate{topic: topics[0], tags: tagFlag, content: strings.Join(args, " ")})
+	default:
+		template := tipTemplate{tags: normalizeTags(tagFlag)}
+		if len(topics) == 1 {
+			template.topic = topics[0]
+		}
+		edited, err := editInTempFile("tips-add-*.txt", template.String())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		template = parseTipTemplate(edited)
+		if template.content == "" {
+			fmt.Println("Empty tip, nothing added")
+			return
+		}
+		templates = append(templates, template)
+	}
+
+	if len(templates) == 0 {
+		fmt.Println("No tips to add")
+		return
+	}
+
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	var added []Tip
+	for _, template := range templates {
+		tip, err := tipsData.addTip(template.topic, template.content, template.tags...)
+		if err != nil {
+			if len(templates) == 1 {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Warning: Skipping %q: %v\n", truncate(template.content, 40), err)
+			continue
+		}
+		tip.Source = sourceManual
+		added = append(added, *tip)
+	}
+
+	if len(added) == 0 {
+		fmt.Println("No tips added")
+		return
+	}
+	if err := store.Add(added...); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(added) == 1 {
+		fmt.Printf("Added tip %s to %s\n", added[0].ID, added[0].Topic)
+	} else {
+		fmt.Printf("Added %d tips to %s\n", len(added), added[0].Topic)
+	}
+}
+
+// readTipLines returns the non-empty lines of r, skipping comments.
+func readTipLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func useEditor(t *testing.T, edit func(contents string) string) {
+	t.Helper()
+	originalEditFile := editFile
+	editFile = func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(path, []byte(edit(string(data))), 0600)
+	}
+	t.Cleanup(func() { editFile = originalEditFile })
+}
+
+func useStdin(t *testing.T, input string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "stdin")
+	if err := os.WriteFile(path, []byte(input), 0600); err != nil {
+		t.Fatalf("Failed to write stdin: %v", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open stdin: %v", err)
+	}
+
+	originalStdin := os.Stdin
+	os.Stdin = f
+	t.Cleanup(func() {
+		os.Stdin = originalStdin
+		f.Close()
+	})
+}
+
+// topicCommand returns a command that was passed topics with --topic.
+func topicCommand(t *testing.T, topics ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{}
+	cmd.Flags().StringSliceVarP(&topicFlag, "topic", "t", []string{}, "")
+	for _, topic := range topics {
+		if err := cmd.Flags().Set("topic", topic); err != nil {
+			t.Fatalf("Failed to set topic: %v", err)
+		}
+	}
+	return cmd
+}
+
+// useAddFlags sets the add flags and returns a command passed topics.
+func useAddFlags(t *testing.T, topics, tags []string) *cobra.Command {
+	t.Helper()
+	originalTopics, originalTags, originalEdit := topicFlag, tagFlag, addEditFlag
+	t.Cleanup(func() { topicFlag, tagFlag, addEditFlag = originalTopics, originalTags, originalEdit })
+	tagFlag, addEditFlag = tags, false
+	return topicCommand(t, topics...)
+}
+
+func TestParseTipTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected tipTemplate
+	}{
+		{
+			name:     "content below the comments",
+			text:     tipTemplate{topic: "git", tags: []string{"stash"}}.String() + "git stash pop\n",
+			expected: tipTemplate{topic: "git", tags: []string{"stash"}, content: "git stash pop"},
+		},
+		{
+			name:     "headers and content",
+			text:     "topic: go/testing\ntags: Table Tests, subtests\n\nUse t.Run: it names subtests\n\n# tips: comment\n",
+			expected: tipTemplate{topic: "go/testing", tags: []string{"table-tests", "subtests"}, content: "Use t.Run: it names subtests"},
+		},
+		{
+			name:     "content with # lines",
+			text:     tipTemplate{topic: "bash", content: "# list files\nls -la\n## Heading"}.String(),
+			expected: tipTemplate{topic: "bash", content: "# list files\nls -la\n## Heading"},
+		},
+		{
+			name:     "content without headers",
+			text:     "Note: git stash pop\nrestores changes",
+			expected: tipTemplate{content: "Note: git stash pop\nrestores changes"},
+		},
+		{
+			name:     "empty",
+			text:     tipTemplate{topic: "git"}.String(),
+			expected: tipTemplate{topic: "git"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseTipTemplate(tt.text); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddTips(t *testing.T) {
+	t.Run("from arguments", func(t *testing.T) {
+		cmd := useAddFlags(t, []string{"git"}, []string{"Branches"})
+		store := useMemoryStore(t)
+
+		output := captureStdout(t, func() { addTips(cmd, []string{"git", "switch", "-"}) })
+		tips, _ := store.Load()
+		if len(tips.Tips) != 1 || !strings.Contains(output, "Added tip "+tips.Tips[0].ID+" to git") {
+			t.Fatalf("Expected one tip to be added, got %+v and '%s'", tips.Tips, output)
+		}
+		if tip := tips.Tips[0]; tip.Content != "git switch -" || tip.Source != sourceManual || !reflect.DeepEqual(tip.Tags, []string{"branches"}) {
+			t.Errorf("Unexpected tip %+v", tip)
+		}
+	})
+
+	t.Run("from stdin", func(t *testing.T) {
+		cmd := useAddFlags(t, []string{"git"}, nil)
+		store := useMemoryStore(t, Tip{ID: "1", Topic: "git", Content: "git stash pop"})
+		useStdin(t, "git log --oneline\n\n# a comment\nGit  stash pop\ngit reflog\ngit log --oneline\n")
+
+		output := captureStdout(t, func() { addTips(cmd, []string{"-"}) })
+		tips, _ := store.Load()
+		if len(tips.Tips) != 3 || !strings.Contains(output, "Added 2 tips to git") {
+			t.Errorf("Expected two new tips with duplicates skipped, got %+v and '%s'", tips.Tips, output)
+		}
+	})
+
+	t.Run("in the editor", func(t *testing.T) {
+		cmd := useAddFlags(t, nil, []string{"wip"})
+		topicFlag = []string{"vim", "go"} // defaults from the config, which add ignores
+		store := useMemoryStore(t)
+
+		var template string
+		useEditor(t, func(contents string) string {
+			template = contents
+			return strings.Replace(contents, "topic: ", "topic: git", 1) + "git stash -p stashes\nchosen hunks\n"
+		})
+		addEditFlag = true
+
+		captureStdout(t, func() { addTips(cmd, nil) })
+		if !strings.HasPrefix(template, "topic: \ntags: wip\n") {
+			t.Errorf("Expected the template to be filled in from the flags, got '%s'", template)
+		}
+		tips, _ := store.Load()
+		if len(tips.Tips) != 1 || tips.Tips[0].Topic != "git" || tips.Tips[0].Content != "git stash -p stashes\nchosen hunks" {
+			t.Errorf("Expected the edited tip to be added, got %+v", tips.Tips)
+		}
+	})
+
+	t.Run("empty edit", func(t *testing.T) {
+		cmd := useAddFlags(t, []string{"git"}, nil)
+		store := useMemoryStore(t)
+		useEditor(t, func(contents string) string { return contents })
+		addEditFlag = true
+
+		output := captureStdout(t, func() { addTips(cmd, nil) })
+		if tips, _ := store.Load(); len(tips.Tips) != 0 || !strings.Contains(output, "nothing added") {
+			t.Errorf("Expected nothing to be added, got %+v and '%s'", tips.Tips, output)
+		}
+	})
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"os"
+	"fmt"
 	"testing"
 )
 
@@ -10,20 +10,20 @@ func BenchmarkTipsData_addTip(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		td.addTip("benchmark", "This is a benchmark tip for performance testing")
+		td.addTip("benchmark", fmt.Sprintf("This is a benchmark tip for performance testing %d", i))
 	}
 }
 
-func BenchmarkTipsData_getRandomTip(b *testing.B) {
+func BenchmarkSRSStrategy_pick(b *testing.B) {
 	td := &TipsData{}
 
 	for i := 0; i < 1000; i++ {
-		td.addTip("benchmark", "Benchmark tip content for performance testing")
+		td.addTip("benchmark", fmt.Sprintf("Benchmark tip content for performance testing %d", i))
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		td.getRandomTip([]string{"benchmark"})
+		srsStrategy{}.pick(td, tipFilter{topics: []string{"benchmark"}})
 	}
 }
 
@@ -32,7 +32,7 @@ func BenchmarkTipsData_removeTip(b *testing.B) {
 	tipIDs := make([]string, b.N)
 
 	for i := 0; i < b.N; i++ {
-		td.addTip("benchmark", "Benchmark tip content for removal testing")
+		td.addTip("benchmark", fmt.Sprintf("Benchmark tip content for removal testing %d", i))
 		if len(td.Tips) > 0 {
 			tipIDs[i] = td.Tips[len(td.Tips)-1].ID
 		}
@@ -46,13 +46,11 @@ func BenchmarkTipsData_removeTip(b *testing.B) {
 
 func BenchmarkLoadTips(b *testing.B) {
 	tmpDir := b.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHome)
+	setTestHome(b, tmpDir)
 
 	testData := &TipsData{}
 	for i := 0; i < 100; i++ {
-		testData.addTip("benchmark", "Benchmark tip content for load testing")
+		testData.addTip("benchmark", fmt.Sprintf("Benchmark tip content for load testing %d", i))
 	}
 
 	if err := saveTips(testData); err != nil {
@@ -70,13 +68,11 @@ func BenchmarkLoadTips(b *testing.B) {
 
 func BenchmarkSaveTips(b *testing.B) {
 	tmpDir := b.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHome)
+	setTestHome(b, tmpDir)
 
 	testData := &TipsData{}
 	for i := 0; i < 100; i++ {
-		testData.addTip("benchmark", "Benchmark tip content for save testing")
+		testData.addTip("benchmark", fmt.Sprintf("Benchmark tip content for save testing %d", i))
 	}
 
 	b.ResetTimer()
@@ -94,13 +90,13 @@ func BenchmarkTipFiltering(b *testing.B) {
 	topics := []string{"git", "vim", "bash", "docker", "kubernetes"}
 	for _, topic := range topics {
 		for i := 0; i < 200; i++ {
-			td.addTip(topic, "Benchmark tip content for filtering testing")
+			td.addTip(topic, fmt.Sprintf("Benchmark tip content for filtering testing %d", i))
 		}
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		td.getRandomTip([]string{"git", "docker"})
+		srsStrategy{}.pick(td, tipFilter{topics: []string{"git", "docker"}})
 	}
 }
 
@@ -108,18 +104,18 @@ func BenchmarkLargeDatasetOperations(b *testing.B) {
 	td := &TipsData{}
 
 	for i := 0; i < 10000; i++ {
-		td.addTip("large-dataset", "This is tip content for large dataset benchmark testing")
+		td.addTip("large-dataset", fmt.Sprintf("This is tip content for large dataset benchmark testing %d", i))
 	}
 
 	b.Run("RandomSelection", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			td.getRandomTip([]string{"large-dataset"})
+			srsStrategy{}.pick(td, tipFilter{topics: []string{"large-dataset"}})
 		}
 	})
 
 	b.Run("FilteredSelection", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
-			td.getRandomTip([]string{"large-dataset", "nonexistent"})
+			srsStrategy{}.pick(td, tipFilter{topics: []string{"large-dataset", "nonexistent"}})
 		}
 	})
 }
@@ -128,13 +124,13 @@ func BenchmarkConcurrentAccess(b *testing.B) {
 	td := &TipsData{}
 
 	for i := 0; i < 1000; i++ {
-		td.addTip("concurrent", "Concurrent access benchmark tip")
+		td.addTip("concurrent", fmt.Sprintf("Concurrent access benchmark tip %d", i))
 	}
 
 	b.ResetTimer()
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			td.getRandomTip([]string{"concurrent"})
+			srsStrategy{}.pick(td, tipFilter{topics: []string{"concurrent"}})
 		}
 	})
 }
@@ -143,13 +139,11 @@ func BenchmarkJSONOperations(b *testing.B) {
 	td := &TipsData{}
 
 	for i := 0; i < 1000; i++ {
-		td.addTip("json-bench", "This is a benchmark tip with realistic content length for JSON serialization testing")
+		td.addTip("json-bench", fmt.Sprintf("This is a benchmark tip with realistic content length for JSON serialization testing %d", i))
 	}
 
 	tmpDir := b.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHome)
+	setTestHome(b, tmpDir)
 
 	b.Run("Save", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
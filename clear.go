@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	clearOlderThanFlag string
+	clearKnownFlag     bool
+	clearSourceFlag    string
+	clearDryRunFlag    bool
+	clearYesFlag       bool
+)
+
+var clearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Delete stored tips",
+	Long: `Delete all tips from local storage, or only those matching every given
+filter.
+
+  --topic, --tag, --not-tag  by topic and tag; default topics from the config
+                             file or profile don't apply
+  --older-than 90d           created more than this long ago (d, w and y
+                             units, or Go durations like 36h)
+  --known                    marked as known
+  --source llm|manual        generated, or added with 'tips add'
+
+The number of tips to delete is shown and confirmed first; --yes skips the
+question, and --dry-run lists the tips without deleting them. Run 'tips undo'
+to bring them back, except from encrypted tips files, which keep no undo
+history.`,
+	Args: cobra.NoArgs,
+	Run:  clearAllTips,
+}
+
+// clearCriteria selects the tips 'tips clear' deletes. Zero values match
+// every tip.
+type clearCriteria struct {
+	filter    tipFilter
+	olderThan time.Duration
+	known     bool
+	source    string
+}
+
+// clearCriteriaFromFlags only uses topics passed to cmd, so that a plain
+// 'tips clear' deletes every tip rather than those in the default topics.
+func clearCriteriaFromFlags(cmd *cobra.Command) (clearCriteria, error) {
+	criteria := clearCriteria{filter: commandFilter(cmd), known: clearKnownFlag, source: clearSourceFlag}
+
+	if clearOlderThanFlag != "" {
+		age, err := parseAge(clearOlderThanFlag)
+		if err != nil {
+			return criteria, err
+		}
+		criteria.olderThan = age
+	}
+	if criteria.source != "" && criteria.source != sourceLLM && criteria.source != sourceManual {
+		return criteria, fmt.Errorf("invalid source %q, expected %s or %s", criteria.source, sourceLLM, sourceManual)
+	}
+	return criteria, nil
+}
+
+func (c clearCriteria) isEmpty() bool {
+	return c.filter.isEmpty() && c.olderThan == 0 && !c.known && c.source == ""
+}
+
+// String describes the criteria for the confirmation prompt.
+func (c clearCriteria) String() string {
+	var parts []string
+	if !c.filter.isEmpty() {
+		parts = append(parts, c.filter.String())
+	}
+	if c.olderThan > 0 {
+		parts = append(parts, "older than "+formatAge(c.olderThan))
+	}
+	if c.known {
+		parts = append(parts, "known")
+	}
+	if c.source != "" {
+		parts = append(parts, "source: "+c.source)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (c clearCriteria) matching(td *TipsData, now time.Time) []Tip {
+	matches := c.filter.matcher()
+	cutoff := now.Add(-c.olderThan)
+
+	var tips []Tip
+	for _, tip := range td.Tips {
+		if c.olderThan > 0 && !tip.CreatedAt.Before(cutoff) {
+			continue
+		}
+		if c.known && !tip.isKnown() {
+			continue
+		}
+		if c.source != "" && tip.Source != c.source {
+			continue
+		}
+		if matches(&tip) {
+			tips = append(tips, tip)
+		}
+	}
+	return tips
+}
+
+var ageUnits = map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour, "y": 365 * 24 * time.Hour}
+
+// parseAge parses durations like 90d, 12w or 1y, as well as anything
+// time.ParseDuration accepts.
+func parseAge(value string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid age %q, expected something like 90d, 12w or 1y", value)
+
+	age, err := time.ParseDuration(value)
+	if err != nil {
+		unit, ok := ageUnits[value[len(value)-1:]]
+		if !ok {
+			return 0, invalid
+		}
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil {
+			return 0, invalid
+		}
+		age = time.Duration(n) * unit
+	}
+
+	if age <= 0 {
+		return 0, fmt.Errorf("age must be greater than 0, got %q", value)
+	}
+	return age, nil
+}
+
+func formatAge(age time.Duration) string {
+	if day := 24 * time.Hour; age%day == 0 {
+		return fmt.Sprintf("%dd", age/day)
+	}
+	return age.String()
+}
+
+// confirm asks question on stdout and reads the answer from stdin. Anything
+// but yes, including no answer at all, declines.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+func clearAllTips(cmd *cobra.Command, args []string) {
+	criteria, err := clearCriteriaFromFlags(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	if len(tipsData.Tips) == 0 {
+		fmt.Println("No tips found - nothing to clear")
+		return
+	}
+
+	tips := criteria.matching(tipsData, time.Now())
+	if len(tips) == 0 {
+		fmt.Printf("No tips found for %s - nothing to clear\n", criteria)
+		return
+	}
+
+	if clearDryRunFlag {
+		for _, tip := range tips {
+			fmt.Printf("%s [%s]\n  %s\n", tip.ID, tip.Topic, tip.Content)
+		}
+		fmt.Printf("Would delete %d of %d tips\n", len(tips), len(tipsData.Tips))
+		return
+	}
+
+	// Encrypted tips files have no journal to undo the deletion from.
+	_, undoable := store.(*journaledStore)
+	if !clearYesFlag {
+		question := fmt.Sprintf("Delete all %d tips?", len(tips))
+		if !criteria.isEmpty() {
+			question = fmt.Sprintf("Delete %d of %d tips (%s)?", len(tips), len(tipsData.Tips), criteria)
+		}
+		if !undoable {
+			question += " This can't be undone."
+		}
+		if !confirm(question) {
+			fmt.Println("Nothing deleted")
+			return
+		}
+	} else if !undoable {
+		fmt.Fprintf(os.Stderr, "Warning: This store keeps no undo history, so the deleted tips can't be restored\n")
+	}
+
+	// Clearing everything goes through Clear, so the store can drop its
+	// files rather than rewriting them.
+	if criteria.isEmpty() {
+		err = store.Clear()
+	} else {
+		_, err = store.Remove(tipIDs(tips)...)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error deleting tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Successfully deleted %d tips\n", len(tips))
+}
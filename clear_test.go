@@ -0,0 +1,127 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := []struct {
+		value       string
+		expected    time.Duration
+		expectError bool
+	}{
+		{value: "90d", expected: 90 * 24 * time.Hour},
+		{value: "2w", expected: 14 * 24 * time.Hour},
+		{value: "1y", expected: 365 * 24 * time.Hour},
+		{value: "36h", expected: 36 * time.Hour},
+		{value: "0d", expectError: true},
+		{value: "-5d", expectError: true},
+		{value: "d", expectError: true},
+		{value: "3 months", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			age, err := parseAge(tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got %v", age)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAge failed: %v", err)
+			}
+			if age != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, age)
+			}
+		})
+	}
+}
+
+func TestClearCriteria_matching(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	known := now.AddDate(0, 0, -1)
+	td := &TipsData{Tips: []Tip{
+		{ID: "1", Topic: "vim", CreatedAt: now.AddDate(0, 0, -100), Source: sourceLLM},
+		{ID: "2", Topic: "vim", CreatedAt: now.AddDate(0, 0, -10), Source: sourceManual},
+		{ID: "3", Topic: "git", CreatedAt: now.AddDate(0, 0, -200), Source: sourceLLM, KnownAt: &known},
+		{ID: "4", Topic: "git", CreatedAt: now, Source: sourceManual},
+	}}
+
+	tests := []struct {
+		name     string
+		criteria clearCriteria
+		expected string
+	}{
+		{name: "everything", criteria: clearCriteria{}, expected: "1,2,3,4"},
+		{name: "topic", criteria: clearCriteria{filter: tipFilter{topics: []string{"vim"}}}, expected: "1,2"},
+		{name: "older than", criteria: clearCriteria{olderThan: 90 * 24 * time.Hour}, expected: "1,3"},
+		{name: "known", criteria: clearCriteria{known: true}, expected: "3"},
+		{name: "source", criteria: clearCriteria{source: sourceManual}, expected: "2,4"},
+		{name: "combined", criteria: clearCriteria{filter: tipFilter{topics: []string{"vim"}}, olderThan: 90 * 24 * time.Hour, source: sourceLLM}, expected: "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(tipIDs(tt.criteria.matching(td, now)), ","); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestClearAllTips_Selective(t *testing.T) {
+	originalTopics, originalDryRun, originalYes := topicFlag, clearDryRunFlag, clearYesFlag
+	defer func() { topicFlag, clearDryRunFlag, clearYesFlag = originalTopics, originalDryRun, originalYes }()
+	clearDryRunFlag, clearYesFlag = false, false
+	cmd := topicCommand(t, "vim")
+
+	store := useMemoryStore(t,
+		Tip{ID: "1", Topic: "vim", Content: "dd deletes a line", CreatedAt: time.Now()},
+		Tip{ID: "2", Topic: "git", Content: "git stash pop", CreatedAt: time.Now()},
+	)
+	remaining := func() int {
+		tips, _ := store.Load()
+		return len(tips.Tips)
+	}
+
+	clearDryRunFlag = true
+	output := captureStdout(t, func() { clearAllTips(cmd, nil) })
+	if !strings.Contains(output, "dd deletes a line") || !strings.Contains(output, "Would delete 1 of 2 tips") || remaining() != 2 {
+		t.Errorf("Expected a dry run listing the vim tip, got '%s'", output)
+	}
+
+	clearDryRunFlag = false
+	useStdin(t, "n\n")
+	output = captureStdout(t, func() { clearAllTips(cmd, nil) })
+	if !strings.Contains(output, "Delete 1 of 2 tips (topics: [vim])? This can't be undone. [y/N]") || !strings.Contains(output, "Nothing deleted") || remaining() != 2 {
+		t.Errorf("Expected the deletion to be declined, got '%s'", output)
+	}
+
+	useStdin(t, "yes\n")
+	output = captureStdout(t, func() { clearAllTips(cmd, nil) })
+	if !strings.Contains(output, "Successfully deleted 1 tips") || remaining() != 1 {
+		t.Errorf("Expected the vim tip to be deleted, got '%s'", output)
+	}
+}
+
+func TestClearAllTips_IgnoresDefaultTopics(t *testing.T) {
+	originalTopics, originalYes := topicFlag, clearYesFlag
+	defer func() { topicFlag, clearYesFlag = originalTopics, originalYes }()
+	clearYesFlag = true
+
+	store := useMemoryStore(t,
+		Tip{ID: "1", Topic: "vim", Content: "dd deletes a line", CreatedAt: time.Now()},
+		Tip{ID: "2", Topic: "git", Content: "git stash pop", CreatedAt: time.Now()},
+	)
+	cmd := topicCommand(t)
+	topicFlag = []string{"vim"} // a default topic from the config
+
+	output := captureStdout(t, func() { clearAllTips(cmd, nil) })
+	if tips, _ := store.Load(); len(tips.Tips) != 0 || !strings.Contains(output, "Successfully deleted 2 tips") {
+		t.Errorf("Expected every tip to be deleted, got %+v and '%s'", tips.Tips, output)
+	}
+}
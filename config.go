@@ -0,0 +1,522 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type Config struct {
+	Model           string              `toml:"model,omitempty"`
+	Count           int                 `toml:"count,omitzero"`
+	Refresh         int                 `toml:"refresh,omitzero"`
+	Topics          []string            `toml:"topics,omitempty"`
+	PromptStyle     string              `toml:"prompt_style,omitempty"`
+	File            string              `toml:"file,omitempty"`
+	Keyfile         string              `toml:"keyfile,omitempty"`
+	Store           string              `toml:"store,omitempty"`
+	Strategy        string              `toml:"strategy,omitempty"`
+	TopicWeights    map[string]float64  `toml:"topic_weights,omitempty"`
+	TopicAliases    map[string]string   `toml:"topic_aliases,omitempty"`
+	FoldTopicCase   bool                `toml:"fold_topic_case,omitempty"`
+	AgeHalfLifeDays int                 `toml:"age_half_life_days,omitzero"`
+	Salt            string              `toml:"salt,omitempty"`
+	CurrentProfile  string              `toml:"current_profile,omitempty"`
+	Profiles        map[string]*Profile `toml:"profiles,omitempty"`
+}
+
+func getConfigPath() (string, error) {
+	if path := os.Getenv("TIPS_CONFIG"); path != "" {
+		return expandPath(path)
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(homeDir, ".config")
+	}
+	return filepath.Join(configHome, "tips", "config.toml"), nil
+}
+
+func loadConfig() (*Config, error) {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config file path: %w", err)
+	}
+
+	var config Config
+	if _, err := toml.DecodeFile(configPath, &config); err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to parse config file %s: %w", configPath, err)
+	}
+	return &config, nil
+}
+
+func saveConfig(config *Config) error {
+	configPath, err := getConfigPath()
+	if err != nil {
+		return fmt.Errorf("failed to get config file path: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(config); err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := writeFileAtomic(configPath, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+// applyConfig fills every flag that wasn't passed on the command line from
+// the active profile, then the config file. Settings that also have an
+// environment variable (model, file, store) are resolved where they're used,
+// from the flag, the environment, the active profile and the config file in
+// that order, so the profile's values are kept apart from the flags.
+func applyConfig(flags *pflag.FlagSet) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	name, profile, err := activeProfile(config)
+	if err != nil {
+		return err
+	}
+
+	activeTopicRules = newTopicRules(config.TopicAliases, config.FoldTopicCase)
+
+	profileFile, profileStore, profileModel = "", "", ""
+	if profile != nil {
+		path, err := getProfileFilePath(name, profile)
+		if err != nil {
+			return fmt.Errorf("failed to get tips file path for profile %s: %w", name, err)
+		}
+		profileFile, profileStore, profileModel = path, profile.Store, profile.Model
+	} else {
+		profile = &Profile{}
+	}
+
+	if !flags.Changed("topic") {
+		if len(profile.Topics) > 0 {
+			topicFlag = profile.Topics
+		} else if len(config.Topics) > 0 {
+			topicFlag = config.Topics
+		}
+	}
+	if !flags.Changed("refresh") {
+		if profile.Refresh > 0 {
+			refreshFlag = profile.Refresh
+		} else if config.Refresh > 0 {
+			refreshFlag = config.Refresh
+		}
+	}
+	if !flags.Changed("count") {
+		if profile.Count > 0 {
+			countFlag = profile.Count
+		} else if config.Count > 0 {
+			countFlag = config.Count
+		}
+	}
+	return nil
+}
+
+type configKey struct {
+	name         string
+	defaultValue string
+	get          func(config *Config) string
+	set          func(config *Config, value string) error
+}
+
+var configKeys = []configKey{
+	{
+		name:         "model",
+		defaultValue: defaultModel,
+		get:          func(config *Config) string { return config.Model },
+		set: func(config *Config, value string) error {
+			if value != "" && len(strings.Split(value, "/")) != 2 {
+				return fmt.Errorf("invalid model format. Expected 'provider/model' (e.g., 'openai/gpt-4o')")
+			}
+			config.Model = value
+			return nil
+		},
+	},
+	{
+		name:         "count",
+		defaultValue: "20",
+		get:          func(config *Config) string { return formatPositiveInt(config.Count) },
+		set: func(config *Config, value string) (err error) {
+			config.Count, err = parsePositiveInt(value)
+			return err
+		},
+	},
+	{
+		name:         "refresh",
+		defaultValue: "60",
+		get:          func(config *Config) string { return formatPositiveInt(config.Refresh) },
+		set: func(config *Config, value string) (err error) {
+			config.Refresh, err = parsePositiveInt(value)
+			return err
+		},
+	},
+	{
+		name: "topics",
+		get:  func(config *Config) string { return strings.Join(config.Topics, ",") },
+		set: func(config *Config, value string) error {
+			config.Topics = nil
+			for _, topic := range strings.Split(value, ",") {
+				if topic = strings.TrimSpace(topic); topic != "" {
+					config.Topics = append(config.Topics, topic)
+				}
+			}
+			return nil
+		},
+	},
+	{
+		name:         "prompt_style",
+		defaultValue: defaultPromptStyle,
+		get:          func(config *Config) string { return config.PromptStyle },
+		set: func(config *Config, value string) error {
+			if _, ok := promptStyles[value]; value != "" && !ok {
+				return fmt.Errorf("unsupported prompt style: %s. Supported styles: %s", value, strings.Join(promptStyleNames(), ", "))
+			}
+			config.PromptStyle = value
+			return nil
+		},
+	},
+	{
+		name: "file",
+		get:  func(config *Config) string { return config.File },
+		set: func(config *Config, value string) (err error) {
+			if value != "" {
+				value, err = expandPath(value)
+			}
+			config.File = value
+			return err
+		},
+	},
+	{
+		name: "keyfile",
+		get:  func(config *Config) string { return config.Keyfile },
+		set: func(config *Config, value string) (err error) {
+			if value != "" {
+				value, err = expandPath(value)
+			}
+			config.Keyfile = value
+			return err
+		},
+	},
+	{
+		name:         "store",
+		defaultValue: "json",
+		get:          func(config *Config) string { return config.Store },
+		set: func(config *Config, value string) error {
+			if value != "" {
+				if err := validateStoreKind(value); err != nil {
+					return err
+				}
+			}
+			config.Store = value
+			return nil
+		},
+	},
+	{
+		name:         "strategy",
+		defaultValue: defaultStrategy,
+		get:          func(config *Config) string { return config.Strategy },
+		set: func(config *Config, value string) error {
+			if value != "" && !slices.Contains(strategyNames, value) {
+				return fmt.Errorf("unsupported strategy: %s. Supported strategies: %s", value, strings.Join(strategyNames, ", "))
+			}
+			config.Strategy = value
+			return nil
+		},
+	},
+	{
+		name: "topic_weights",
+		get:  func(config *Config) string { return formatTopicWeights(config.TopicWeights) },
+		set: func(config *Config, value string) (err error) {
+			config.TopicWeights, err = parseTopicWeights(value)
+			return err
+		},
+	},
+	{
+		name: "topic_aliases",
+		get:  func(config *Config) string { return formatTopicAliases(config.TopicAliases) },
+		set: func(config *Config, value string) (err error) {
+			config.TopicAliases, err = parseTopicAliases(value)
+			return err
+		},
+	},
+	{
+		name:         "fold_topic_case",
+		defaultValue: "false",
+		get: func(config *Config) string {
+			if config.FoldTopicCase {
+				return "true"
+			}
+			return ""
+		},
+		set: func(config *Config, value string) (err error) {
+			if value == "" {
+				config.FoldTopicCase = false
+				return nil
+			}
+			if config.FoldTopicCase, err = strconv.ParseBool(value); err != nil {
+				return fmt.Errorf("expected true or false, got %q", value)
+			}
+			return nil
+		},
+	},
+	{
+		name: "age_half_life_days",
+		get:  func(config *Config) string { return formatPositiveInt(config.AgeHalfLifeDays) },
+		set: func(config *Config, value string) (err error) {
+			config.AgeHalfLifeDays, err = parsePositiveInt(value)
+			return err
+		},
+	},
+	{
+		name: "salt",
+		get:  func(config *Config) string { return config.Salt },
+		set: func(config *Config, value string) error {
+			config.Salt = value
+			return nil
+		},
+	},
+}
+
+func findConfigKey(name string) (configKey, error) {
+	for _, key := range configKeys {
+		if key.name == name {
+			return key, nil
+		}
+	}
+
+	names := make([]string, len(configKeys))
+	for i, key := range configKeys {
+		names[i] = key.name
+	}
+	return configKey{}, fmt.Errorf("unknown config key: %s. Supported keys: %s", name, strings.Join(names, ", "))
+}
+
+func parsePositiveInt(value string) (int, error) {
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("expected a positive number, got %q", value)
+	}
+	return n, nil
+}
+
+func formatPositiveInt(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// parseTopicWeights reads weights written as "kubernetes=3,vim=1".
+func parseTopicWeights(value string) (map[string]float64, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	weights := make(map[string]float64)
+	for _, pair := range strings.Split(value, ",") {
+		topic, weight, ok := strings.Cut(pair, "=")
+		topic = strings.TrimSpace(topic)
+		if !ok || topic == "" {
+			return nil, fmt.Errorf("expected topic=weight, got %q", pair)
+		}
+		w, err := strconv.ParseFloat(strings.TrimSpace(weight), 64)
+		if err != nil || w < 0 || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("expected a non-negative weight for %s, got %q", topic, weight)
+		}
+		weights[topic] = w
+	}
+	return weights, nil
+}
+
+func formatTopicWeights(weights map[string]float64) string {
+	topics := make([]string, 0, len(weights))
+	for topic := range weights {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+
+	pairs := make([]string, len(topics))
+	for i, topic := range topics {
+		pairs[i] = topic + "=" + strconv.FormatFloat(weights[topic], 'g', -1, 64)
+	}
+	return strings.Join(pairs, ",")
+}
+
+// parseTopicAliases reads aliases written as "golang=go,k8s=kubernetes".
+func parseTopicAliases(value string) (map[string]string, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	aliases := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		alias, topic, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected alias=topic, got %q", pair)
+		}
+		alias, err := normalizeTopic(alias)
+		if err != nil {
+			return nil, fmt.Errorf("invalid alias %q: %w", pair, err)
+		}
+		topic, err = normalizeTopic(topic)
+		if err != nil {
+			return nil, fmt.Errorf("invalid topic for alias %s: %w", alias, err)
+		}
+		aliases[alias] = topic
+	}
+	return aliases, nil
+}
+
+func formatTopicAliases(aliases map[string]string) string {
+	names := make([]string, 0, len(aliases))
+	for alias := range aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, len(names))
+	for i, alias := range names {
+		pairs[i] = alias + "=" + aliases[alias]
+	}
+	return strings.Join(pairs, ",")
+}
+
+func getConfigValue(name string) (string, error) {
+	key, err := findConfigKey(name)
+	if err != nil {
+		return "", err
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	if value := key.get(config); value != "" {
+		return value, nil
+	}
+	return key.defaultValue, nil
+}
+
+// setConfigValue updates a single key; an empty value removes it.
+func setConfigValue(name, value string) error {
+	key, err := findConfigKey(name)
+	if err != nil {
+		return err
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if err := key.set(config, strings.TrimSpace(value)); err != nil {
+		return err
+	}
+	return saveConfig(config)
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and edit the config file",
+	Long: `Inspect and edit the config file ($XDG_CONFIG_HOME/tips/config.toml, or TIPS_CONFIG).
+
+The config file sets defaults for model, count, refresh, topics, prompt_style,
+file, keyfile, store, strategy, topic_weights, topic_aliases, fold_topic_case,
+age_half_life_days and salt. Flags take precedence over environment variables,
+then the active profile, then the config file.`,
+	// Config commands must work even when the config refers to a missing profile.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print a config value",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := getConfigValue(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a config value (an empty value removes it)",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := setConfigValue(args[0], args[1]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating config: %v\n", err)
+			os.Exit(1)
+		}
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all config values",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		config, err := loadConfig()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, key := range configKeys {
+			if value := key.get(config); value != "" {
+				fmt.Printf("%s = %s\n", key.name, value)
+			} else if key.defaultValue != "" {
+				fmt.Printf("%s = %s (default)\n", key.name, key.defaultValue)
+			} else {
+				fmt.Printf("%s =\n", key.name)
+			}
+		}
+	},
+}
+
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the config file path",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, err := getConfigPath()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error getting config file path: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(path)
+	},
+}
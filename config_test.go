@@ -0,0 +1,215 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeTestConfig(t testing.TB, contents string) string {
+	t.Helper()
+
+	configPath, err := getConfigPath()
+	if err != nil {
+		t.Fatalf("Failed to get config path: %v", err)
+	}
+	if contents == "" {
+		return configPath
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatalf("Failed to create config directory: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte(contents), 0644); err != nil {
+		t.Fatalf("Failed to write config file: %v", err)
+	}
+	return configPath
+}
+
+func TestGetConfigPath(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	path, err := getConfigPath()
+	if err != nil {
+		t.Fatalf("getConfigPath failed: %v", err)
+	}
+	if expected := filepath.Join(homeDir, ".config", "tips", "config.toml"); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(homeDir, "xdg"))
+	path, err = getConfigPath()
+	if err != nil {
+		t.Fatalf("getConfigPath failed: %v", err)
+	}
+	if expected := filepath.Join(homeDir, "xdg", "tips", "config.toml"); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+
+	override := filepath.Join(homeDir, "custom.toml")
+	t.Setenv("TIPS_CONFIG", override)
+	path, err = getConfigPath()
+	if err != nil {
+		t.Fatalf("getConfigPath failed: %v", err)
+	}
+	if path != override {
+		t.Errorf("Expected %s, got %s", override, path)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		setTestHome(t, t.TempDir())
+
+		config, err := loadConfig()
+		if err != nil {
+			t.Fatalf("loadConfig failed: %v", err)
+		}
+		if config.File != "" || config.Store != "" {
+			t.Errorf("Expected empty config, got %+v", config)
+		}
+	})
+
+	t.Run("valid file", func(t *testing.T) {
+		setTestHome(t, t.TempDir())
+		writeTestConfig(t, "file = \"~/work-tips.json\"\nstore = \"sqlite\"\n")
+
+		config, err := loadConfig()
+		if err != nil {
+			t.Fatalf("loadConfig failed: %v", err)
+		}
+		if config.File != "~/work-tips.json" {
+			t.Errorf("Expected file '~/work-tips.json', got %q", config.File)
+		}
+		if config.Store != "sqlite" {
+			t.Errorf("Expected store 'sqlite', got %q", config.Store)
+		}
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		setTestHome(t, t.TempDir())
+		writeTestConfig(t, "file = ")
+
+		if _, err := loadConfig(); err == nil {
+			t.Error("Expected error for invalid config file")
+		}
+	})
+}
+
+func TestApplyConfig_Defaults(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	useProfileFlags(t)
+
+	writeTestConfig(t, `
+topics = ["git"]
+refresh = 30
+count = 10
+
+[profiles.work]
+count = 5
+`)
+
+	if err := applyConfig(testFlagSet(t, "--refresh", "45")); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+	if !reflect.DeepEqual(topicFlag, []string{"git"}) || refreshFlag != 45 || countFlag != 10 {
+		t.Errorf("Expected topics [git], refresh 45 and count 10, got %v, %d and %d", topicFlag, refreshFlag, countFlag)
+	}
+
+	profileFlag = "work"
+	if err := applyConfig(testFlagSet(t)); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+	if countFlag != 5 {
+		t.Errorf("Expected profile count to take precedence over config, got %d", countFlag)
+	}
+}
+
+func TestApplyConfig_TopicRules(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	useProfileFlags(t)
+
+	writeTestConfig(t, `
+fold_topic_case = true
+
+[topic_aliases]
+Golang = "Go"
+`)
+
+	if err := applyConfig(testFlagSet(t)); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+	if topic, err := canonicalTopic(" golang / Testing "); err != nil || topic != "go/testing" {
+		t.Errorf("Expected go/testing, got %q (%v)", topic, err)
+	}
+}
+
+func TestConfigValues(t *testing.T) {
+	setTestHome(t, t.TempDir())
+
+	tests := []struct {
+		key         string
+		value       string
+		expected    string
+		expectError bool
+	}{
+		{key: "model", value: "", expected: defaultModel},
+		{key: "model", value: "anthropic/claude-3", expected: "anthropic/claude-3"},
+		{key: "model", value: "gpt-4o", expectError: true},
+		{key: "count", value: "15", expected: "15"},
+		{key: "count", value: "0", expectError: true},
+		{key: "refresh", value: "abc", expectError: true},
+		{key: "topics", value: "git, vim,,", expected: "git,vim"},
+		{key: "prompt_style", value: "detailed", expected: "detailed"},
+		{key: "prompt_style", value: "haiku", expectError: true},
+		{key: "store", value: "sqlite", expected: "sqlite"},
+		{key: "store", value: "postgres", expectError: true},
+		{key: "store", value: "", expected: "json"},
+		{key: "strategy", value: "weighted", expected: "weighted"},
+		{key: "strategy", value: "fifo", expectError: true},
+		{key: "topic_weights", value: "vim=1, kubernetes=3", expected: "kubernetes=3,vim=1"},
+		{key: "topic_weights", value: "vim", expectError: true},
+		{key: "topic_weights", value: "vim=-1", expectError: true},
+		{key: "topic_aliases", value: "golang=go, K8s = kubernetes", expected: "K8s=kubernetes,golang=go"},
+		{key: "topic_aliases", value: "golang", expectError: true},
+		{key: "topic_aliases", value: "golang=go/*", expectError: true},
+		{key: "fold_topic_case", value: "true", expected: "true"},
+		{key: "fold_topic_case", value: "", expected: "false"},
+		{key: "fold_topic_case", value: "sometimes", expectError: true},
+		{key: "age_half_life_days", value: "30", expected: "30"},
+		{key: "colour", value: "blue", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			err := setConfigValue(tt.key, tt.value)
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("setConfigValue failed: %v", err)
+			}
+
+			value, err := getConfigValue(tt.key)
+			if err != nil {
+				t.Fatalf("getConfigValue failed: %v", err)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, value)
+			}
+		})
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	if config.Model != "anthropic/claude-3" || config.Count != 15 || config.Store != "" {
+		t.Errorf("Unexpected config after updates: %+v", config)
+	}
+}
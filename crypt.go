@@ -0,0 +1,199 @@
+package main
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const tipsEncryption = "scrypt-aes-256-gcm"
+
+var (
+	errNoEncryptionKey = errors.New("tips file is encrypted. Set TIPS_PASSPHRASE or TIPS_KEYFILE (or keyfile in the config file) to decrypt it")
+	errDecrypt         = errors.New("failed to decrypt tips file: wrong passphrase or keyfile")
+)
+
+// The schema version is kept outside the ciphertext so version checks work
+// without the key.
+type encryptedTipsFile struct {
+	SchemaVersion int    `json:"schema_version"`
+	Encryption    string `json:"encryption"`
+	Salt          []byte `json:"salt"`
+	Nonce         []byte `json:"nonce"`
+	Data          []byte `json:"data"`
+}
+
+// Deriving a key is deliberately slow, so keys are cached per secret and salt.
+var derivedKeys sync.Map
+
+func isEncrypted(data []byte) bool {
+	var header struct {
+		Encryption string `json:"encryption"`
+	}
+	return json.Unmarshal(data, &header) == nil && header.Encryption != ""
+}
+
+func getEncryptionSecret() ([]byte, error) {
+	if passphrase := os.Getenv("TIPS_PASSPHRASE"); passphrase != "" {
+		return []byte(passphrase), nil
+	}
+
+	keyfile := os.Getenv("TIPS_KEYFILE")
+	if keyfile == "" {
+		config, err := loadConfig()
+		if err != nil {
+			return nil, err
+		}
+		keyfile = config.Keyfile
+	}
+	if keyfile == "" {
+		return nil, errNoEncryptionKey
+	}
+
+	path, err := expandPath(keyfile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve keyfile path: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read keyfile: %w", err)
+	}
+
+	secret := []byte(strings.TrimSpace(string(data)))
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("keyfile %s is empty", path)
+	}
+	return secret, nil
+}
+
+func newTipsCipher(salt []byte) (cipher.AEAD, error) {
+	secret, err := getEncryptionSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := string(secret) + "\x00" + string(salt)
+	key, ok := derivedKeys.Load(cacheKey)
+	if !ok {
+		derived, err := scrypt.Key(secret, salt, 1<<15, 8, 1, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive encryption key: %w", err)
+		}
+		key, _ = derivedKeys.LoadOrStore(cacheKey, derived)
+	}
+
+	block, err := aes.NewCipher(key.([]byte))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptTipsData(plaintext []byte, schemaVersion int) ([]byte, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := newTipsCipher(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	return json.MarshalIndent(encryptedTipsFile{
+		SchemaVersion: schemaVersion,
+		Encryption:    tipsEncryption,
+		Salt:          salt,
+		Nonce:         nonce,
+		Data:          gcm.Seal(nil, nonce, plaintext, nil),
+	}, "", "  ")
+}
+
+func decryptTipsData(data []byte) ([]byte, error) {
+	var file encryptedTipsFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+	if file.Encryption != tipsEncryption {
+		return nil, fmt.Errorf("unsupported tips file encryption: %s", file.Encryption)
+	}
+
+	gcm, err := newTipsCipher(file.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("invalid tips file nonce")
+	}
+
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Data, nil)
+	if err != nil {
+		return nil, errDecrypt
+	}
+	return plaintext, nil
+}
+
+// setTipsFileEncryption rewrites the tips file encrypted or in plaintext.
+// The backup is replaced too, so no copy is left in the old format, and the
+// undo journal and search index are removed when encrypting.
+func setTipsFileEncryption(path string, encrypt bool) (int, error) {
+	store := newJSONFileStore(path)
+
+	unlock, err := store.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	_, encrypted := store.diskFormat()
+	if encrypt && encrypted {
+		return 0, fmt.Errorf("%s is already encrypted", path)
+	}
+	if !encrypt && !encrypted {
+		return 0, fmt.Errorf("%s is not encrypted", path)
+	}
+
+	tipsData, err := store.load()
+	if err != nil {
+		return 0, err
+	}
+	if err := checkSchemaWritable(tipsData.SchemaVersion); err != nil {
+		return 0, err
+	}
+
+	data, err := store.encode(tipsData, encrypt)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return 0, fmt.Errorf("failed to write tips file: %w", err)
+	}
+	if err := writeFileAtomic(store.backupPath(), data, 0600); err != nil {
+		return 0, fmt.Errorf("failed to write tips backup: %w", err)
+	}
+
+	// The undo journal and search index keep tips in plain text.
+	if encrypt {
+		if err := os.Remove(journalPath(path)); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("failed to remove undo journal: %w", err)
+		}
+		if err := os.Remove(searchIndexPath(path)); err != nil && !os.IsNotExist(err) {
+			return 0, fmt.Errorf("failed to remove search index: %w", err)
+		}
+	}
+	return len(tipsData.Tips), nil
+}
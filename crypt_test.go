@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetTipsFileEncryption(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	t.Setenv("TIPS_PASSPHRASE", "correct horse battery staple")
+
+	path := filepath.Join(t.TempDir(), "tips.json")
+	store := newJSONFileStore(path)
+	if err := store.Add(Tip{ID: "1", Topic: "ops", Content: "ssh internal.example.com"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	count, err := setTipsFileEncryption(path, true)
+	if err != nil {
+		t.Fatalf("setTipsFileEncryption failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1 tip encrypted, got %d", count)
+	}
+
+	for _, file := range []string{path, store.backupPath()} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		if !isEncrypted(data) || strings.Contains(string(data), "internal.example.com") {
+			t.Errorf("Expected %s to be encrypted, got %s", file, data)
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			t.Fatalf("Failed to stat %s: %v", file, err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Expected %s permissions 0600, got %v", file, info.Mode().Perm())
+		}
+	}
+
+	if _, err := setTipsFileEncryption(path, true); err == nil {
+		t.Error("Expected error encrypting an encrypted file")
+	}
+
+	if err := store.Add(Tip{ID: "2", Topic: "ops", Content: "kubectl -n internal get pods"}); err != nil {
+		t.Fatalf("Add to encrypted file failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read tips file: %v", err)
+	}
+	if !isEncrypted(data) {
+		t.Error("Expected file to stay encrypted after a write")
+	}
+
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(tipsData.Tips) != 2 || tipsData.Tips[0].Content != "ssh internal.example.com" {
+		t.Errorf("Expected 2 decrypted tips, got %+v", tipsData.Tips)
+	}
+
+	if _, err := setTipsFileEncryption(path, false); err != nil {
+		t.Fatalf("Decrypt failed: %v", err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read tips file: %v", err)
+	}
+	if isEncrypted(data) || !strings.Contains(string(data), "internal.example.com") {
+		t.Errorf("Expected plain JSON after decrypt, got %s", data)
+	}
+}
+
+func TestEncryptedFile_StaysEncryptedAfterClear(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+	t.Setenv("TIPS_PASSPHRASE", "correct horse battery staple")
+
+	path, err := getTipsFilePath()
+	if err != nil {
+		t.Fatalf("getTipsFilePath failed: %v", err)
+	}
+	if err := newJSONFileStore(path).Add(Tip{ID: "1", Topic: "ops", Content: "ssh internal.example.com"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := setTipsFileEncryption(path, true); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+
+	store, err := openStore()
+	if err != nil {
+		t.Fatalf("openStore failed: %v", err)
+	}
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+
+	store, err = openStore()
+	if err != nil {
+		t.Fatalf("openStore failed: %v", err)
+	}
+	if err := store.Add(Tip{ID: "2", Topic: "ops", Content: "kubectl -n internal get pods"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read tips file: %v", err)
+	}
+	if !isEncrypted(data) || strings.Contains(string(data), "kubectl") {
+		t.Errorf("Expected the tips file to stay encrypted, got %s", data)
+	}
+	if _, err := os.Stat(journalPath(path)); !os.IsNotExist(err) {
+		t.Errorf("Expected no journal for an encrypted file, got %v", err)
+	}
+
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(tipsData.Tips) != 1 || tipsData.Tips[0].ID != "2" {
+		t.Errorf("Expected only the new tip, got %+v", tipsData.Tips)
+	}
+}
+
+func TestEncryptedFile_KeyErrors(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	t.Setenv("TIPS_PASSPHRASE", "right")
+
+	path := filepath.Join(t.TempDir(), "tips.json")
+	store := newJSONFileStore(path)
+	if err := store.Add(Tip{ID: "1", Topic: "ops", Content: "secret"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := setTipsFileEncryption(path, true); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	original, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read tips file: %v", err)
+	}
+
+	t.Setenv("TIPS_PASSPHRASE", "wrong")
+	if _, err := store.Load(); !errors.Is(err, errDecrypt) {
+		t.Errorf("Expected decrypt error, got %v", err)
+	}
+
+	t.Setenv("TIPS_PASSPHRASE", "")
+	if _, err := store.Load(); !errors.Is(err, errNoEncryptionKey) {
+		t.Errorf("Expected missing key error, got %v", err)
+	}
+	if err := store.Save(&TipsData{}); !errors.Is(err, errNoEncryptionKey) {
+		t.Errorf("Expected Save without key to fail, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read tips file: %v", err)
+	}
+	if string(data) != string(original) {
+		t.Error("Encrypted file should be untouched after key errors")
+	}
+}
+
+func TestEncryptedFile_Keyfile(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	keyfile := filepath.Join(homeDir, "tips.key")
+	if err := os.WriteFile(keyfile, []byte("keyfile secret\n"), 0600); err != nil {
+		t.Fatalf("Failed to write keyfile: %v", err)
+	}
+	writeTestConfig(t, `keyfile = "~/tips.key"`)
+
+	path := filepath.Join(t.TempDir(), "tips.json")
+	if err := newJSONFileStore(path).Add(Tip{ID: "1", Topic: "ops", Content: "secret"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := setTipsFileEncryption(path, true); err != nil {
+		t.Fatalf("Encrypt with keyfile failed: %v", err)
+	}
+
+	tipsData, err := newJSONFileStore(path).Load()
+	if err != nil {
+		t.Fatalf("Load with keyfile failed: %v", err)
+	}
+	if len(tipsData.Tips) != 1 {
+		t.Errorf("Expected 1 tip, got %d", len(tipsData.Tips))
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+var editCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit a tip in $EDITOR",
+	Long: `Open a tip's topic, tags and content in $VISUAL or $EDITOR, and save the
+changes back to the store when the editor exits.
+
+The tip can be given by its full ID or a unique prefix of at least 4
+characters, as can the IDs given to rm, tag and known restore. Edits are
+checked like new tips, and can be reverted with 'tips undo'.`,
+	Args: cobra.ExactArgs(1),
+	Run:  editTipCmd,
+}
+
+var rmCmd = &cobra.Command{
+	Use:   "rm <id>...",
+	Short: "Delete tips by ID or ID prefix",
+	Long: `Delete tips by their full IDs or unique prefixes of at least 4 characters.
+Nothing is deleted if any ID doesn't match exactly one tip. Deleted tips can be
+restored with 'tips undo'.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  removeTips,
+}
+
+func editTipCmd(cmd *cobra.Command, args []string) {
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	tip, err := tipsData.resolveTip(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	original := tipTemplate{topic: tip.Topic, tags: tip.Tags, content: tip.Content}
+	edited, err := editInTempFile("tips-edit-*.txt", original.String())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	template := parseTipTemplate(edited)
+	if template.content == "" {
+		fmt.Println("Empty tip, nothing changed")
+		return
+	}
+	if template.topic == original.topic && slices.Equal(template.tags, normalizeTags(original.tags)) && template.content == original.content {
+		fmt.Println("No changes")
+		return
+	}
+
+	tip, err = tipsData.editTip(tip.ID, template.topic, template.content, template.tags)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := store.Update(*tip); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving tips: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Updated tip %s\n", tip.ID)
+}
+
+func removeTips(cmd *cobra.Command, args []string) {
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	ids, err := tipsData.resolveTipIDs(args)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	removed, err := store.Remove(ids...)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error deleting tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	if removed == 1 {
+		fmt.Printf("Deleted tip %s\n", ids[0])
+		return
+	}
+	fmt.Printf("Deleted %d tips\n", removed)
+}
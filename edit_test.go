@@ -0,0 +1,131 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestTipsData_resolveTip(t *testing.T) {
+	td := &TipsData{Tips: []Tip{
+		{ID: "3f2a9c1e-0000-4000-8000-000000000001", Topic: "git"},
+		{ID: "3f2a9c1e-0000-4000-8000-000000000002", Topic: "git"},
+		{ID: "b71c04d2-0000-4000-8000-000000000003", Topic: "vim"},
+		{ID: "1", Topic: "go"},
+	}}
+
+	tests := []struct {
+		ref         string
+		expected    string
+		expectError string
+	}{
+		{ref: "b71c04d2-0000-4000-8000-000000000003", expected: "b71c04d2-0000-4000-8000-000000000003"},
+		{ref: "b71c", expected: "b71c04d2-0000-4000-8000-000000000003"},
+		{ref: " B71C04 ", expected: "b71c04d2-0000-4000-8000-000000000003"},
+		{ref: "3f2a9c1e-0000-4000-8000-000000000002", expected: "3f2a9c1e-0000-4000-8000-000000000002"},
+		{ref: "1", expected: "1"},
+		{ref: "3f2a", expectError: "ambiguous"},
+		{ref: "b71", expectError: "at least 4"},
+		{ref: "ffff", expectError: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			tip, err := td.resolveTip(tt.ref)
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveTip failed: %v", err)
+			}
+			if tip.ID != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, tip.ID)
+			}
+		})
+	}
+}
+
+func TestTipsData_editTip(t *testing.T) {
+	td := &TipsData{Tips: []Tip{
+		{ID: "1", Topic: "git", Content: "git stash pop"},
+		{ID: "2", Topic: "git", Content: "git stash apply"},
+	}}
+
+	if _, err := td.editTip("2", "git", "Git stash  pop", nil); err == nil {
+		t.Error("Expected an edit duplicating another tip to fail")
+	}
+	if _, err := td.editTip("2", "git/*", "git stash apply", nil); err == nil {
+		t.Error("Expected an invalid topic to fail")
+	}
+
+	tip, err := td.editTip("2", " git/stash ", "git stash apply keeps the stash ", []string{"Stash"})
+	if err != nil {
+		t.Fatalf("editTip failed: %v", err)
+	}
+	expected := Tip{ID: "2", Topic: "git/stash", Content: "git stash apply keeps the stash", Tags: []string{"stash"}}
+	if !reflect.DeepEqual(*tip, expected) || !reflect.DeepEqual(td.Tips[1], expected) {
+		t.Errorf("Expected %+v, got %+v", expected, td.Tips[1])
+	}
+
+	if _, err := td.addTip("git/stash", "git stash apply keeps the stash"); err == nil {
+		t.Error("Expected the edited tip to count for duplicate detection")
+	}
+}
+
+func TestEditTipCmd(t *testing.T) {
+	store := useMemoryStore(t, Tip{ID: "a1b2c3d4", Topic: "git", Content: "git stash pop", Tags: []string{"stash"}})
+
+	var template string
+	useEditor(t, func(contents string) string {
+		template = contents
+		return strings.Replace(contents, "git stash pop", "git stash pop restores the latest stash", 1)
+	})
+
+	output := captureStdout(t, func() { editTipCmd(&cobra.Command{}, []string{"a1b2"}) })
+	if !strings.HasPrefix(template, "topic: git\ntags: stash\n\ngit stash pop\n") {
+		t.Errorf("Expected the tip in the template, got '%s'", template)
+	}
+	tips, _ := store.Load()
+	if tips.Tips[0].Content != "git stash pop restores the latest stash" || !strings.Contains(output, "Updated tip a1b2c3d4") {
+		t.Errorf("Expected the tip to be updated, got %+v and '%s'", tips.Tips[0], output)
+	}
+
+	useEditor(t, func(contents string) string { return contents })
+	output = captureStdout(t, func() { editTipCmd(&cobra.Command{}, []string{"a1b2c3d4"}) })
+	if !strings.Contains(output, "No changes") {
+		t.Errorf("Expected no changes, got '%s'", output)
+	}
+}
+
+func TestRemoveTips(t *testing.T) {
+	store := useMemoryStore(t,
+		Tip{ID: "a1b2c3d4", Topic: "git", Content: "git stash pop"},
+		Tip{ID: "e5f6a7b8", Topic: "vim", Content: "dd deletes a line"},
+		Tip{ID: "c9d0e1f2", Topic: "go", Content: "go vet finds bugs"},
+	)
+
+	output := captureStdout(t, func() { removeTips(&cobra.Command{}, []string{"a1b2", "E5F6A7B8"}) })
+	tips, _ := store.Load()
+	if len(tips.Tips) != 1 || tips.Tips[0].ID != "c9d0e1f2" || !strings.Contains(output, "Deleted 2 tips") {
+		t.Errorf("Expected two tips to be deleted, got %+v and '%s'", tips.Tips, output)
+	}
+}
+
+func TestEditTipCmd_KeepsHashLines(t *testing.T) {
+	content := "# stash everything, untracked files too\ngit stash -u"
+	store := useMemoryStore(t, Tip{ID: "a1b2c3d4", Topic: "git", Content: content})
+	useEditor(t, func(contents string) string {
+		return strings.Replace(contents, "stash -u", "stash --include-untracked", 1)
+	})
+
+	captureStdout(t, func() { editTipCmd(&cobra.Command{}, []string{"a1b2"}) })
+	tips, _ := store.Load()
+	if expected := "# stash everything, untracked files too\ngit stash --include-untracked"; tips.Tips[0].Content != expected {
+		t.Errorf("Expected %q, got %q", expected, tips.Tips[0].Content)
+	}
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormatFlag string
+	exportOutputFlag string
+)
+
+var exportFormats = []string{"md", "csv", "jsonl", "html"}
+
+// exportExtensions picks the format from the output file when --format isn't
+// given.
+var exportExtensions = map[string]string{
+	".md":       "md",
+	".markdown": "md",
+	".csv":      "csv",
+	".jsonl":    "jsonl",
+	".html":     "html",
+	".htm":      "html",
+}
+
+// maxTermLength is the longest "term: description" prefix the Markdown and
+// HTML cheat sheets highlight, in characters.
+const maxTermLength = 40
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export tips as a Markdown, CSV, JSONL or HTML cheat sheet",
+	Long: `Export tips to share them with people who don't use tips.
+
+  md     a Markdown cheat sheet, with a section per topic
+  html   the same cheat sheet as a standalone web page
+  csv    one row per tip, for spreadsheets
+  jsonl  one JSON tip per line, for other tools
+
+Tips are grouped by topic and sorted alphabetically within each topic, and
+` + "`code spans`" + ` in tips are kept as code. Use --topic, --tag and --not-tag to
+export some of the tips. The format defaults to the extension of the -o file,
+or md.`,
+	Args: cobra.NoArgs,
+	Run:  exportTips,
+}
+
+// sortForExport orders tips by topic, then content, ignoring case.
+func sortForExport(tips []Tip) {
+	sort.SliceStable(tips, func(i, j int) bool {
+		a, b := &tips[i], &tips[j]
+		if at, bt := strings.ToLower(a.Topic), strings.ToLower(b.Topic); at != bt {
+			return at < bt
+		}
+		if ac, bc := strings.ToLower(a.Content), strings.ToLower(b.Content); ac != bc {
+			return ac < bc
+		}
+		return a.CreatedAt.Before(b.CreatedAt)
+	})
+}
+
+type topicGroup struct {
+	Topic string
+	Tips  []Tip
+}
+
+// groupByTopic groups sorted tips by topic, ignoring case, and names each
+// group after the first spelling seen.
+func groupByTopic(tips []Tip) []topicGroup {
+	var groups []topicGroup
+	for _, tip := range tips {
+		if n := len(groups); n > 0 && strings.EqualFold(groups[n-1].Topic, tip.Topic) {
+			groups[n-1].Tips = append(groups[n-1].Tips, tip)
+			continue
+		}
+		groups = append(groups, topicGroup{Topic: tip.Topic, Tips: []Tip{tip}})
+	}
+	return groups
+}
+
+// A textSpan is a run of plain text, or a code span including its
+// backticks.
+type textSpan struct {
+	text string
+	code bool
+}
+
+// splitCodeSpans splits s into plain text and code spans. As in Markdown, a
+// span opened by a run of backticks is closed by a run of the same length,
+// and a run that isn't closed is plain text.
+func splitCodeSpans(s string) []textSpan {
+	var spans []textSpan
+	start := 0
+	for i := 0; i < len(s); {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		n := backtickRun(s, i)
+		end := findBacktickRun(s, i+n, n)
+		if end < 0 {
+			i += n
+			continue
+		}
+
+		if start < i {
+			spans = append(spans, textSpan{text: s[start:i]})
+		}
+		spans = append(spans, textSpan{text: s[i : end+n], code: true})
+		i = end + n
+		start = i
+	}
+	if start < len(s) {
+		spans = append(spans, textSpan{text: s[start:]})
+	}
+	return spans
+}
+
+func backtickRun(s string, i int) int {
+	n := 0
+	for i+n < len(s) && s[i+n] == '`' {
+		n++
+	}
+	return n
+}
+
+// findBacktickRun returns the index of the first run of exactly n backticks
+// at or after i, or -1.
+func findBacktickRun(s string, i, n int) int {
+	for i < len(s) {
+		if s[i] != '`' {
+			i++
+			continue
+		}
+		run := backtickRun(s, i)
+		if run == n {
+			return i
+		}
+		i += run
+	}
+	return -1
+}
+
+// codeSpanText returns the code in a span, without its backticks and with
+// line breaks as spaces, dropping one space of padding as Markdown does.
+func codeSpanText(span string) string {
+	n := backtickRun(span, 0)
+	code := strings.ReplaceAll(span[n:len(span)-n], "\n", " ")
+	if len(code) >= 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.TrimSpace(code) != "" {
+		code = code[1 : len(code)-1]
+	}
+	return code
+}
+
+// cheatsheetTerm splits a tip written as "term: description", the style
+// generated tips use, so the term can be highlighted.
+func cheatsheetTerm(content string) (term, description string, ok bool) {
+	term, description, ok = strings.Cut(content, ": ")
+	if !ok || term == "" || len([]rune(term)) > maxTermLength || strings.ContainsAny(term, "`\n") {
+		return "", content, false
+	}
+	return term, description, true
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`,
+)
+
+func renderMarkdown(s string) string {
+	var b strings.Builder
+	for _, span := range splitCodeSpans(s) {
+		if span.code {
+			b.WriteString(span.text)
+		} else {
+			b.WriteString(markdownEscaper.Replace(span.text))
+		}
+	}
+	return b.String()
+}
+
+func renderHTML(s string) string {
+	var b strings.Builder
+	for _, span := range splitCodeSpans(s) {
+		if span.code {
+			b.WriteString("<code>" + html.EscapeString(codeSpanText(span.text)) + "</code>")
+		} else {
+			b.WriteString(strings.ReplaceAll(html.EscapeString(span.text), "\n", "<br>\n"))
+		}
+	}
+	return b.String()
+}
+
+func writeMarkdown(w io.Writer, tips []Tip) error {
+	var b strings.Builder
+	b.WriteString("# Tips\n")
+	for _, group := range groupByTopic(tips) {
+		fmt.Fprintf(&b, "\n## %s\n\n", markdownEscaper.Replace(group.Topic))
+		for _, tip := range group.Tips {
+			item := renderMarkdown(tip.Content)
+			if term, description, ok := cheatsheetTerm(tip.Content); ok {
+				item = "**" + markdownEscaper.Replace(term) + "**: " + renderMarkdown(description)
+			}
+			// Indent continuation lines to keep them in the list item.
+			fmt.Fprintf(&b, "- %s\n", strings.ReplaceAll(item, "\n", "\n  "))
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("tips").Funcs(template.FuncMap{
+	"tip": func(content string) template.HTML {
+		if term, description, ok := cheatsheetTerm(content); ok {
+			return template.HTML("<strong>" + html.EscapeString(term) + "</strong>: " + renderHTML(description))
+		}
+		return template.HTML(renderHTML(content))
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>Tips</title>
+<style>
+body { font-family: system-ui, sans-serif; line-height: 1.5; max-width: 50rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+h2 { border-bottom: 1px solid #ddd; margin-top: 2rem; }
+li { margin: 0.25rem 0; }
+code { font-family: ui-monospace, monospace; background: #f3f3f3; padding: 0.1rem 0.3rem; border-radius: 3px; }
+</style>
+</head>
+<body>
+<h1>Tips</h1>
+{{- range .}}
+<section>
+<h2>{{.Topic}}</h2>
+<ul>
+{{- range .Tips}}
+<li>{{tip .Content}}</li>
+{{- end}}
+</ul>
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, tips []Tip) error {
+	return htmlTemplate.Execute(w, groupByTopic(tips))
+}
+
+func writeCSV(w io.Writer, tips []Tip) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"id", "topic", "content", "tags", "source", "created_at", "known_at"})
+	for _, tip := range tips {
+		knownAt := ""
+		if tip.KnownAt != nil {
+			knownAt = tip.KnownAt.Format(time.RFC3339)
+		}
+		cw.Write([]string{tip.ID, tip.Topic, tip.Content, strings.Join(tip.Tags, ", "), tip.Source, tip.CreatedAt.Format(time.RFC3339), knownAt})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeJSONL(w io.Writer, tips []Tip) error {
+	enc := json.NewEncoder(w)
+	for _, tip := range tips {
+		if err := enc.Encode(tip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// exportFormat returns the format to export in, from --format or the
+// output file's extension.
+func exportFormat(format, output string) (string, error) {
+	if format == "" {
+		if format = exportExtensions[strings.ToLower(filepath.Ext(output))]; format == "" {
+			format = "md"
+		}
+	}
+	if !slices.Contains(exportFormats, format) {
+		return "", fmt.Errorf("invalid format %q, expected one of: %s", format, strings.Join(exportFormats, ", "))
+	}
+	return format, nil
+}
+
+func writeExport(w io.Writer, format string, tips []Tip) error {
+	switch format {
+	case "csv":
+		return writeCSV(w, tips)
+	case "jsonl":
+		return writeJSONL(w, tips)
+	case "html":
+		return writeHTML(w, tips)
+	default:
+		return writeMarkdown(w, tips)
+	}
+}
+
+func exportTips(cmd *cobra.Command, args []string) {
+	format, err := exportFormat(exportFormatFlag, exportOutputFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	tipsData, err := loadTips()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	matches := commandFilter(cmd).matcher()
+	var tips []Tip
+	for _, tip := range tipsData.Tips {
+		if matches(&tip) {
+			tips = append(tips, tip)
+		}
+	}
+	if len(tips) == 0 {
+		fmt.Fprintln(os.Stderr, "No tips to export")
+		return
+	}
+	sortForExport(tips)
+
+	if exportOutputFlag == "" || exportOutputFlag == "-" {
+		if err := writeExport(os.Stdout, format, tips); err != nil {
+			fmt.Fprintf(os.Stderr, "Error exporting tips: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := writeExport(&buf, format, tips); err != nil {
+		fmt.Fprintf(os.Stderr, "Error exporting tips: %v\n", err)
+		os.Exit(1)
+	}
+	path, err := expandPath(exportOutputFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if err := writeFileAtomic(path, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+		os.Exit(1)
+	}
+	fmt.Printf("Exported %d tips to %s\n", len(tips), path)
+}
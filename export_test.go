@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSplitCodeSpans(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected []textSpan
+	}{
+		{text: "no code", expected: []textSpan{{text: "no code"}}},
+		{text: "run `go vet` first", expected: []textSpan{{text: "run "}, {text: "`go vet`", code: true}, {text: " first"}}},
+		{text: "``a ` b``", expected: []textSpan{{text: "``a ` b``", code: true}}},
+		{text: "an ` unmatched backtick", expected: []textSpan{{text: "an ` unmatched backtick"}}},
+		{text: "``a` b", expected: []textSpan{{text: "``a` b"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			if got := splitCodeSpans(tt.text); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheatsheetTerm(t *testing.T) {
+	tests := []struct {
+		content  string
+		term     string
+		expected bool
+	}{
+		{content: "git stash: Temporarily save changes", term: "git stash", expected: true},
+		{content: "See https://go.dev for docs", expected: false},
+		{content: "`a: b`: code in the term", expected: false},
+		{content: "A long sentence that happens to go on and on before a colon: here", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			term, _, ok := cheatsheetTerm(tt.content)
+			if ok != tt.expected || term != tt.term {
+				t.Errorf("Expected %q (%v), got %q (%v)", tt.term, tt.expected, term, ok)
+			}
+		})
+	}
+}
+
+func exportTestTips() []Tip {
+	tips := []Tip{
+		{ID: "1", Topic: "vim", Content: "vim: Delete a line with `dd`"},
+		{ID: "2", Topic: "git", Content: "git stash: Save changes with `git stash`, restore them with `git stash pop`"},
+		{ID: "3", Topic: "Git", Content: "Use *.go globs with <care>\nand a second line", Tags: []string{"globs"}},
+	}
+	sortForExport(tips)
+	return tips
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var b strings.Builder
+	if err := writeMarkdown(&b, exportTestTips()); err != nil {
+		t.Fatalf("writeMarkdown failed: %v", err)
+	}
+
+	expected := "# Tips\n\n## git\n\n" +
+		"- **git stash**: Save changes with `git stash`, restore them with `git stash pop`\n" +
+		"- Use \\*.go globs with \\<care\\>\n  and a second line\n" +
+		"\n## vim\n\n" +
+		"- **vim**: Delete a line with `dd`\n"
+	if b.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var b strings.Builder
+	if err := writeHTML(&b, exportTestTips()); err != nil {
+		t.Fatalf("writeHTML failed: %v", err)
+	}
+	output := b.String()
+
+	for _, want := range []string{
+		"<h2>git</h2>",
+		"<li><strong>git stash</strong>: Save changes with <code>git stash</code>, restore them with <code>git stash pop</code></li>",
+		"<li>Use *.go globs with &lt;care&gt;<br>\nand a second line</li>",
+		"<li><strong>vim</strong>: Delete a line with <code>dd</code></li>",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "<h2>git</h2>") > strings.Index(output, "<h2>vim</h2>") || strings.Count(output, "<h2>") != 2 {
+		t.Errorf("Expected one section per topic in order, got:\n%s", output)
+	}
+}
+
+func TestExportFormat(t *testing.T) {
+	tests := []struct {
+		format      string
+		output      string
+		expected    string
+		expectError bool
+	}{
+		{expected: "md"},
+		{output: "tips.HTML", expected: "html"},
+		{output: "tips.txt", expected: "md"},
+		{format: "csv", output: "tips.html", expected: "csv"},
+		{format: "pdf", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"/"+tt.output, func(t *testing.T) {
+			format, err := exportFormat(tt.format, tt.output)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got %s", format)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("exportFormat failed: %v", err)
+			}
+			if format != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, format)
+			}
+		})
+	}
+}
+
+func TestExportTips(t *testing.T) {
+	originalTopics, originalFormat, originalOutput := topicFlag, exportFormatFlag, exportOutputFlag
+	defer func() { topicFlag, exportFormatFlag, exportOutputFlag = originalTopics, originalFormat, originalOutput }()
+	exportFormatFlag = ""
+	cmd := topicCommand(t, "git")
+
+	created := time.Date(2024, 5, 1, 9, 30, 0, 0, time.UTC)
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "git stash pop, then\n\"git stash drop\"", CreatedAt: created, Tags: []string{"stash", "cleanup"}, Source: sourceLLM},
+		Tip{ID: "2", Topic: "vim", Content: "dd deletes a line", CreatedAt: created},
+	)
+
+	t.Run("csv", func(t *testing.T) {
+		exportOutputFlag = filepath.Join(t.TempDir(), "tips.csv")
+		output := captureStdout(t, func() { exportTips(cmd, nil) })
+		if !strings.Contains(output, "Exported 1 tips to "+exportOutputFlag) {
+			t.Errorf("Expected the export to be reported, got '%s'", output)
+		}
+
+		f, err := os.Open(exportOutputFlag)
+		if err != nil {
+			t.Fatalf("Failed to open export: %v", err)
+		}
+		defer f.Close()
+		records, err := csv.NewReader(f).ReadAll()
+		if err != nil {
+			t.Fatalf("Failed to read CSV: %v", err)
+		}
+		expected := [][]string{
+			{"id", "topic", "content", "tags", "source", "created_at", "known_at"},
+			{"1", "git", "git stash pop, then\n\"git stash drop\"", "stash, cleanup", "llm", "2024-05-01T09:30:00Z", ""},
+		}
+		if !reflect.DeepEqual(records, expected) {
+			t.Errorf("Expected %q, got %q", expected, records)
+		}
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		exportOutputFlag = filepath.Join(t.TempDir(), "tips.jsonl")
+		captureStdout(t, func() { exportTips(cmd, nil) })
+
+		f, err := os.Open(exportOutputFlag)
+		if err != nil {
+			t.Fatalf("Failed to open export: %v", err)
+		}
+		defer f.Close()
+		var tips []Tip
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var tip Tip
+			if err := json.Unmarshal(scanner.Bytes(), &tip); err != nil {
+				t.Fatalf("Failed to decode line %q: %v", scanner.Text(), err)
+			}
+			tips = append(tips, tip)
+		}
+		if len(tips) != 1 || tips[0].ID != "1" || !tips[0].CreatedAt.Equal(created) {
+			t.Errorf("Expected the git tip, got %+v", tips)
+		}
+	})
+}
+
+func TestExportTips_IgnoresDefaultTopics(t *testing.T) {
+	originalTopics, originalFormat, originalOutput := topicFlag, exportFormatFlag, exportOutputFlag
+	defer func() { topicFlag, exportFormatFlag, exportOutputFlag = originalTopics, originalFormat, originalOutput }()
+	setTestHome(t, t.TempDir())
+	writeTestConfig(t, "topics = [\"git\"]\n")
+	if err := applyConfig(testFlagSet(t)); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+	exportFormatFlag, exportOutputFlag = "", filepath.Join(t.TempDir(), "tips.md")
+
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "git stash pop"},
+		Tip{ID: "2", Topic: "vim", Content: "dd deletes a line"},
+	)
+
+	output := captureStdout(t, func() { exportTips(&cobra.Command{}, nil) })
+	if !strings.Contains(output, "Exported 2 tips") {
+		t.Errorf("Expected every tip to be exported, got '%s'", output)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	tmpDir := t.TempDir()
+	filePath := filepath.Join(tmpDir, "tips.json")
+
+	if err := writeFileAtomic(filePath, []byte("first"), 0644); err != nil {
+		t.Fatalf("writeFileAtomic failed: %v", err)
+	}
+
+	if err := writeFileAtomic(filePath, []byte("second"), 0600); err != nil {
+		t.Fatalf("writeFileAtomic failed to replace file: %v", err)
+	}
+
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		t.Fatalf("Failed to read file: %v", err)
+	}
+	if string(data) != "second" {
+		t.Errorf("Expected content 'second', got '%s'", data)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(filePath)
+		if err != nil {
+			t.Fatalf("Failed to stat file: %v", err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("Expected permissions 0600, got %v", info.Mode().Perm())
+		}
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("Expected only the target file in directory, found %d entries", len(entries))
+	}
+}
+
+func TestWriteFileAtomic_MissingDirectory(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "missing", "tips.json")
+
+	if err := writeFileAtomic(filePath, []byte("data"), 0644); err == nil {
+		t.Error("Expected error when directory does not exist")
+	}
+}
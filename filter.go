@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// A tipFilter selects tips by topic and tag. A tip passes if its topic
+// matches one of the topic patterns (see topicMatches), it has at least one
+// of tags and none of notTags; empty fields match every tip.
+type tipFilter struct {
+	topics  []string
+	tags    []string
+	notTags []string
+}
+
+// flagFilter returns the filter given by --topic, --tag and --not-tag.
+func flagFilter() tipFilter {
+	return tipFilter{topics: topicFlag, tags: tagFlag, notTags: notTagFlag}
+}
+
+// explicitTopics returns the topics passed to cmd with --topic, leaving out
+// the defaults applyConfig fills in from the config file and profile, so a
+// default topic never silently decides where tips are written.
+func explicitTopics(cmd *cobra.Command) []string {
+	if !cmd.Flags().Changed("topic") {
+		return nil
+	}
+	return topicFlag
+}
+
+// commandFilter is flagFilter with only the topics passed to cmd, for
+// commands that look at every tip unless told otherwise.
+func commandFilter(cmd *cobra.Command) tipFilter {
+	return tipFilter{topics: explicitTopics(cmd), tags: tagFlag, notTags: notTagFlag}
+}
+
+func (f tipFilter) isEmpty() bool {
+	return len(f.topics) == 0 && len(f.tags) == 0 && len(f.notTags) == 0
+}
+
+// String describes the filter for "no tips found" messages.
+func (f tipFilter) String() string {
+	var parts []string
+	if len(f.topics) > 0 {
+		parts = append(parts, fmt.Sprintf("topics: %v", f.topics))
+	}
+	if len(f.tags) > 0 {
+		parts = append(parts, fmt.Sprintf("tags: %v", f.tags))
+	}
+	if len(f.notTags) > 0 {
+		parts = append(parts, fmt.Sprintf("excluding tags: %v", f.notTags))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// matcher builds the lookup sets once, so the returned function doesn't
+// allocate per tip.
+func (f tipFilter) matcher() func(tip *Tip) bool {
+	var topicPatterns []string
+	for _, topic := range f.topics {
+		topicPatterns = append(topicPatterns, normalizeTopicPattern(topic))
+	}
+	tagSet := newIDSet(normalizeTags(f.tags))
+	notTagSet := newIDSet(normalizeTags(f.notTags))
+
+	return func(tip *Tip) bool {
+		if len(topicPatterns) > 0 && !matchesAnyTopic(topicPatterns, tip.Topic) {
+			return false
+		}
+
+		matched := len(tagSet) == 0
+		for _, tag := range tip.Tags {
+			if _, excluded := notTagSet[tag]; excluded {
+				return false
+			}
+			if _, ok := tagSet[tag]; ok {
+				matched = true
+			}
+		}
+		return matched
+	}
+}
+
+// normalizeTags lowercases tags, joins words with dashes and drops empty and
+// duplicate tags, keeping the first occurrence's position.
+func normalizeTags(tags []string) []string {
+	var normalized []string
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if tag == "" {
+			continue
+		}
+		if _, dup := seen[tag]; dup {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTipFilter_matcher(t *testing.T) {
+	tip := &Tip{Topic: "git", Tags: []string{"stash", "branch"}}
+
+	tests := []struct {
+		name     string
+		filter   tipFilter
+		expected bool
+	}{
+		{name: "empty filter", filter: tipFilter{}, expected: true},
+		{name: "matching topic", filter: tipFilter{topics: []string{"vim", "git"}}, expected: true},
+		{name: "other topic", filter: tipFilter{topics: []string{"vim"}}, expected: false},
+		{name: "matching tag", filter: tipFilter{tags: []string{"stash"}}, expected: true},
+		{name: "any of the tags", filter: tipFilter{tags: []string{"rebase", "branch"}}, expected: true},
+		{name: "missing tag", filter: tipFilter{tags: []string{"rebase"}}, expected: false},
+		{name: "tags are normalized", filter: tipFilter{tags: []string{"#Stash"}}, expected: true},
+		{name: "excluded tag", filter: tipFilter{notTags: []string{"branch"}}, expected: false},
+		{name: "other excluded tag", filter: tipFilter{notTags: []string{"rebase"}}, expected: true},
+		{name: "exclusion wins", filter: tipFilter{tags: []string{"stash"}, notTags: []string{"branch"}}, expected: false},
+		{name: "topic and tag", filter: tipFilter{topics: []string{"vim"}, tags: []string{"stash"}}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if matched := tt.filter.matcher()(tip); matched != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, matched)
+			}
+		})
+	}
+
+	untagged := &Tip{Topic: "git"}
+	if !(tipFilter{}).matcher()(untagged) || (tipFilter{tags: []string{"stash"}}).matcher()(untagged) {
+		t.Error("Expected untagged tips to match only filters without tags")
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		tags     []string
+		expected []string
+	}{
+		{tags: nil, expected: nil},
+		{tags: []string{"Git", " stash ", "", "#git"}, expected: []string{"git", "stash"}},
+		{tags: []string{"Interactive  Rebase"}, expected: []string{"interactive-rebase"}},
+		{tags: []string{"# Stash", "  #git ", "#"}, expected: []string{"stash", "git"}},
+	}
+
+	for _, tt := range tests {
+		if normalized := normalizeTags(tt.tags); !reflect.DeepEqual(normalized, tt.expected) {
+			t.Errorf("normalizeTags(%q): expected %q, got %q", tt.tags, tt.expected, normalized)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var historyLimitFlag int
+
+// A viewEntry records a tip being shown.
+type viewEntry struct {
+	TipID string    `json:"tip_id"`
+	Time  time.Time `json:"time"`
+}
+
+// viewLog is an append-only JSON Lines file next to the store.
+type viewLog struct {
+	path string
+}
+
+func viewLogPath(storePath string) string {
+	return storePath + ".views"
+}
+
+func openViewLog() (*viewLog, error) {
+	storePath, err := getStorePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tips store path: %w", err)
+	}
+	return &viewLog{path: viewLogPath(storePath)}, nil
+}
+
+func (l *viewLog) append(tipID string) error {
+	line, err := json.Marshal(viewEntry{TipID: tipID, Time: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal view: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create view log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open view log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write view log: %w", err)
+	}
+	return nil
+}
+
+// entries skips lines it can't parse, like the undo journal.
+func (l *viewLog) entries() ([]viewEntry, error) {
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open view log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []viewEntry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var entry viewEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil && entry.TipID != "" {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read view log: %w", err)
+	}
+	return entries, nil
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List recently viewed tips",
+	Long: `List the tips shown by 'tips show', most recent first.
+
+Every tip shown is logged with the time it was shown. Filter with --topic,
+--tag and --not-tag, and choose how many views to list with --limit.`,
+	Args: cobra.NoArgs,
+	Run:  listHistory,
+}
+
+func listHistory(cmd *cobra.Command, args []string) {
+	if historyLimitFlag <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: Limit must be greater than 0\n")
+		os.Exit(1)
+	}
+
+	views, err := openViewLog()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening view log: %v\n", err)
+		os.Exit(1)
+	}
+	entries, err := views.entries()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading view log: %v\n", err)
+		os.Exit(1)
+	}
+
+	tipsData, err := loadTips()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+	filter := flagFilter()
+	matches := filter.matcher()
+
+	listed := 0
+	for i := len(entries) - 1; i >= 0 && listed < historyLimitFlag; i-- {
+		entry := entries[i]
+		viewed := entry.Time.Local().Format("2006-01-02 15:04")
+
+		tip := tipsData.findTip(entry.TipID)
+		if tip == nil {
+			if filter.isEmpty() {
+				fmt.Printf("%s  %s (deleted)\n", viewed, entry.TipID)
+				listed++
+			}
+			continue
+		}
+		if !matches(tip) {
+			continue
+		}
+
+		fmt.Printf("%s  %s [%s]\n  %s\n", viewed, tip.ID, tip.Topic, tip.Content)
+		listed++
+	}
+
+	if listed == 0 {
+		fmt.Println("No tips viewed yet")
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestViewLog(t *testing.T) {
+	views := &viewLog{path: filepath.Join(t.TempDir(), "tips.json.views")}
+
+	entries, err := views.entries()
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("Expected no views for a missing log, got %v, %v", entries, err)
+	}
+
+	for _, id := range []string{"1", "2"} {
+		if err := views.append(id); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	f, err := os.OpenFile(views.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("Failed to open view log: %v", err)
+	}
+	f.WriteString(`{"tip_id": "3", "ti`)
+	f.Close()
+
+	entries, err = views.entries()
+	if err != nil {
+		t.Fatalf("entries failed: %v", err)
+	}
+	if len(entries) != 2 || entries[0].TipID != "1" || entries[1].TipID != "2" {
+		t.Errorf("Expected views 1 and 2, got %+v", entries)
+	}
+}
+
+func TestListHistory(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	originalTopics, originalLimit := topicFlag, historyLimitFlag
+	defer func() { topicFlag, historyLimitFlag = originalTopics, originalLimit }()
+	topicFlag, historyLimitFlag = []string{}, 20
+
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "git stash pop"},
+		Tip{ID: "2", Topic: "vim", Content: "dd deletes a line"},
+	)
+
+	output := captureStdout(t, func() { listHistory(&cobra.Command{}, nil) })
+	if !strings.Contains(output, "No tips viewed yet") {
+		t.Errorf("Expected no views, got '%s'", output)
+	}
+
+	views, err := openViewLog()
+	if err != nil {
+		t.Fatalf("openViewLog failed: %v", err)
+	}
+	for _, id := range []string{"1", "2", "gone"} {
+		if err := views.append(id); err != nil {
+			t.Fatalf("append failed: %v", err)
+		}
+	}
+
+	output = captureStdout(t, func() { listHistory(&cobra.Command{}, nil) })
+	gone, vim, git := strings.Index(output, "gone (deleted)"), strings.Index(output, "dd deletes a line"), strings.Index(output, "git stash pop")
+	if gone == -1 || vim == -1 || git == -1 || !(gone < vim && vim < git) {
+		t.Errorf("Expected views listed most recent first, got '%s'", output)
+	}
+
+	topicFlag = []string{"git"}
+	output = captureStdout(t, func() { listHistory(&cobra.Command{}, nil) })
+	if !strings.Contains(output, "git stash pop") || strings.Contains(output, "dd deletes a line") || strings.Contains(output, "gone") {
+		t.Errorf("Expected only the git view, got '%s'", output)
+	}
+
+	topicFlag, historyLimitFlag = []string{}, 1
+	output = captureStdout(t, func() { listHistory(&cobra.Command{}, nil) })
+	if strings.Count(output, "\n") != 1 {
+		t.Errorf("Expected a single view, got '%s'", output)
+	}
+}
@@ -5,10 +5,12 @@ package main
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -78,9 +80,7 @@ func TestCLIIntegration(t *testing.T) {
 
 func TestFileSystemIntegration(t *testing.T) {
 	tmpDir := t.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHome)
+	setTestHome(t, tmpDir)
 
 	tipsData := &TipsData{
 		Tips: []Tip{
@@ -126,12 +126,9 @@ func TestFileSystemIntegration(t *testing.T) {
 
 func TestConcurrentAccess(t *testing.T) {
 	tmpDir := t.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHome)
+	setTestHome(t, tmpDir)
 
-	// Test only concurrent reads to avoid race conditions from concurrent writes
-	numGoroutines := 3 // Further reduced to minimize race conditions
+	numGoroutines := 3
 	errChan := make(chan error, numGoroutines)
 
 	// Create initial tips file with proper data
@@ -149,7 +146,7 @@ func TestConcurrentAccess(t *testing.T) {
 		t.Fatalf("Failed to create initial tips file: %v", err)
 	}
 
-	// Only test concurrent reads (no concurrent writes to avoid file corruption)
+	// Test concurrent reads
 	for i := 0; i < numGoroutines; i++ {
 		go func() {
 			_, err := loadTips()
@@ -185,18 +182,100 @@ func TestConcurrentAccess(t *testing.T) {
 			t.Errorf("Sequential write %d failed: %v", i, err)
 		}
 	}
+
+	// Concurrent writers must not lose each other's changes
+	t.Run("concurrent writers", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), ".tips.json")
+
+		initial := &TipsData{}
+		for i := 0; i < 10; i++ {
+			initial.addTip("initial", fmt.Sprintf("initial tip %d", i))
+		}
+		if err := newJSONFileStore(filePath).Save(initial); err != nil {
+			t.Fatalf("Failed to create initial tips file: %v", err)
+		}
+
+		// A stale snapshot, like the one held by a long-running TUI.
+		stale, err := newJSONFileStore(filePath).Load()
+		if err != nil {
+			t.Fatalf("Failed to load tips: %v", err)
+		}
+
+		const writers = 8
+		const tipsPerWriter = 10
+
+		var wg sync.WaitGroup
+		errChan := make(chan error, writers*tipsPerWriter+len(stale.Tips))
+
+		for w := 0; w < writers; w++ {
+			wg.Add(1)
+			go func(w int) {
+				defer wg.Done()
+				// Each writer uses its own store, as separate processes would.
+				store := newJSONFileStore(filePath)
+				for i := 0; i < tipsPerWriter; i++ {
+					batch := &TipsData{}
+					batch.addTip("generated", fmt.Sprintf("writer %d tip %d", w, i))
+					errChan <- store.Add(batch.Tips...)
+				}
+			}(w)
+		}
+
+		for i := range stale.Tips[:5] {
+			wg.Add(1)
+			go func(id string) {
+				defer wg.Done()
+				_, err := newJSONFileStore(filePath).Remove(id)
+				errChan <- err
+			}(stale.Tips[i].ID)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stale.Tips = append(stale.Tips, Tip{ID: "from-stale", Topic: "tui", Content: "added by a stale snapshot", CreatedAt: time.Now()})
+			errChan <- newJSONFileStore(filePath).Save(stale)
+		}()
+
+		wg.Wait()
+		close(errChan)
+
+		for err := range errChan {
+			if err != nil {
+				t.Errorf("Concurrent write failed: %v", err)
+			}
+		}
+
+		final, err := newJSONFileStore(filePath).Load()
+		if err != nil {
+			t.Fatalf("Failed to load final tips: %v", err)
+		}
+
+		counts := make(map[string]int)
+		for _, tip := range final.Tips {
+			counts[tip.Topic]++
+		}
+
+		if counts["generated"] != writers*tipsPerWriter {
+			t.Errorf("Expected %d generated tips, got %d", writers*tipsPerWriter, counts["generated"])
+		}
+		if counts["initial"] != 5 {
+			t.Errorf("Expected 5 initial tips to remain, got %d", counts["initial"])
+		}
+		if counts["tui"] != 1 {
+			t.Errorf("Expected the stale snapshot's tip to be saved, got %d", counts["tui"])
+		}
+	})
 }
 
 func TestLargeDatasetPerformance(t *testing.T) {
 	tmpDir := t.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHome)
+	setTestHome(t, tmpDir)
 
 	// Create large dataset
 	tipsData := &TipsData{}
 	for i := 0; i < 1000; i++ {
-		tipsData.addTip("performance", "Performance test tip number "+string(rune('0'+i%10)))
+		tipsData.addTip("performance", fmt.Sprintf("Performance test tip number %d", i))
 	}
 
 	// Test save performance
@@ -228,7 +307,7 @@ func TestLargeDatasetPerformance(t *testing.T) {
 	// Test random tip selection performance
 	start = time.Now()
 	for i := 0; i < 100; i++ {
-		tipsData.getRandomTip([]string{"performance"})
+		srsStrategy{}.pick(tipsData, tipFilter{topics: []string{"performance"}})
 	}
 	selectionTime := time.Since(start)
 
@@ -239,9 +318,7 @@ func TestLargeDatasetPerformance(t *testing.T) {
 
 func TestMalformedJSONHandling(t *testing.T) {
 	tmpDir := t.TempDir()
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", tmpDir)
-	defer os.Setenv("HOME", originalHome)
+	setTestHome(t, tmpDir)
 
 	// Create malformed JSON file
 	filePath, err := getTipsFilePath()
@@ -249,6 +326,10 @@ func TestMalformedJSONHandling(t *testing.T) {
 		t.Fatalf("Failed to get tips file path: %v", err)
 	}
 
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		t.Fatalf("Failed to create tips directory: %v", err)
+	}
+
 	malformedJSON := `{"tips": [{"id": "test", "topic": "test"` // Missing closing
 	err = os.WriteFile(filePath, []byte(malformedJSON), 0644)
 	if err != nil {
@@ -281,9 +362,7 @@ func TestPermissionHandling(t *testing.T) {
 	}
 	defer os.Chmod(restrictedDir, 0755) // Restore permissions for cleanup
 
-	originalHome := os.Getenv("HOME")
-	os.Setenv("HOME", restrictedDir)
-	defer os.Setenv("HOME", originalHome)
+	setTestHome(t, restrictedDir)
 
 	// Test save with permission error
 	tipsData := &TipsData{
@@ -358,7 +437,7 @@ func TestMemoryUsage(t *testing.T) {
 
 	// Add many tips to test memory efficiency
 	for i := 0; i < 10000; i++ {
-		tipsData.addTip("memory-test", "This is a memory test tip with some content to test memory usage")
+		tipsData.addTip("memory-test", fmt.Sprintf("This is memory test tip %d with some content to test memory usage", i))
 	}
 
 	if len(tipsData.Tips) != 10000 {
@@ -368,7 +447,7 @@ func TestMemoryUsage(t *testing.T) {
 	// Test random access doesn't degrade too much
 	start := time.Now()
 	for i := 0; i < 100; i++ { // Reduced iterations for reasonable performance
-		tipsData.getRandomTip([]string{"memory-test"})
+		srsStrategy{}.pick(&tipsData, tipFilter{topics: []string{"memory-test"}})
 	}
 	duration := time.Since(start)
 
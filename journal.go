@@ -0,0 +1,484 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/gofrs/flock"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+const (
+	opAdd    = "add"
+	opRemove = "remove"
+	opClear  = "clear"
+	opEdit   = "edit"
+	opUndo   = "undo"
+	opRedo   = "redo"
+)
+
+// maxJournalEntries is how many operations the journal keeps. Once it grows
+// past this, it's compacted to the most recent operations that can be undone.
+const maxJournalEntries = 1000
+
+var (
+	errNothingToUndo = errors.New("nothing to undo")
+	errNothingToRedo = errors.New("nothing to redo")
+)
+
+// A journalEntry records the tips an operation added, removed or (for edits)
+// their new versions, with the previous versions in Before. Undo and redo
+// entries point at the entry they reverted or reapplied.
+type journalEntry struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	Op     string    `json:"op"`
+	Tips   []Tip     `json:"tips,omitempty"`
+	Before []Tip     `json:"before,omitempty"`
+	Target string    `json:"target,omitempty"`
+}
+
+type journal struct {
+	path string
+}
+
+func journalPath(storePath string) string {
+	return storePath + ".journal"
+}
+
+// lock serializes journaled writes between processes, so that the tips an
+// entry records are the ones the write actually changed, and compaction
+// doesn't drop another process's entry.
+func (j *journal) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	fileLock := flock.New(j.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("failed to lock journal: %w", err)
+	}
+	return func() { fileLock.Unlock() }, nil
+}
+
+func (j *journal) append(entry journalEntry) error {
+	entry.ID = uuid.New().String()
+	entry.Time = time.Now()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal entry: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(j.path), 0755); err != nil {
+		return fmt.Errorf("failed to create journal directory: %w", err)
+	}
+
+	f, err := os.OpenFile(j.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	return nil
+}
+
+// entries skips lines it can't parse, so a torn final write doesn't lose the
+// rest of the history.
+func (j *journal) entries() ([]journalEntry, error) {
+	f, err := os.Open(j.path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	defer f.Close()
+
+	var entries []journalEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// compact rewrites the journal as the operations that can be undone, dropping
+// all but the most recent maxJournalEntries, once it has grown past that.
+// It's called after a new operation, so there's nothing to redo.
+func (j *journal) compact() error {
+	data, err := os.ReadFile(j.path)
+	if err != nil {
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	if bytes.Count(data, []byte{'\n'}) <= maxJournalEntries {
+		return nil
+	}
+
+	undo, _, err := j.stacks()
+	if err != nil {
+		return err
+	}
+	undo = undo[max(len(undo)-maxJournalEntries, 0):]
+
+	data = nil
+	for _, entry := range undo {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal journal entry: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+	if err := writeFileAtomic(j.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to compact journal: %w", err)
+	}
+	return nil
+}
+
+// stacks replays the journal and returns the operations that can be undone
+// and redone, most recent last. A new operation clears the redo stack.
+func (j *journal) stacks() (undo, redo []journalEntry, err error) {
+	entries, err := j.entries()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, entry := range entries {
+		switch entry.Op {
+		case opUndo:
+			if n := len(undo); n > 0 && undo[n-1].ID == entry.Target {
+				redo = append(redo, undo[n-1])
+				undo = undo[:n-1]
+			}
+		case opRedo:
+			if n := len(redo); n > 0 && redo[n-1].ID == entry.Target {
+				undo = append(undo, redo[n-1])
+				redo = redo[:n-1]
+			}
+		default:
+			undo = append(undo, entry)
+			redo = nil
+		}
+	}
+	return undo, redo, nil
+}
+
+// journaledStore records every Add, Update, Remove and Clear so it can be
+// undone. Save replaces the collection wholesale and is not journaled, and
+// neither are updates that only change a tip's review state, so undo skips
+// over grades, ratings and known marks.
+type journaledStore struct {
+	TipStore
+	journal *journal
+}
+
+func newJournaledStore(store TipStore, path string) *journaledStore {
+	return &journaledStore{TipStore: store, journal: &journal{path: path}}
+}
+
+func (s *journaledStore) Add(tips ...Tip) error {
+	unlock, err := s.journal.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := s.TipStore.Add(tips...); err != nil {
+		return err
+	}
+	return s.record(opAdd, tips, nil)
+}
+
+func (s *journaledStore) Update(tips ...Tip) error {
+	unlock, err := s.journal.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := s.TipStore.Load()
+	if err != nil {
+		return err
+	}
+
+	var updated, before []Tip
+	for _, tip := range tips {
+		if existing := current.findTip(tip.ID); existing != nil && !onlyReviewStateChanged(*existing, tip) {
+			updated = append(updated, tip)
+			before = append(before, *existing)
+		}
+	}
+
+	if err := s.TipStore.Update(tips...); err != nil {
+		return err
+	}
+	return s.record(opEdit, updated, before)
+}
+
+func (s *journaledStore) Remove(ids ...string) (int, error) {
+	unlock, err := s.journal.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	current, err := s.TipStore.Load()
+	if err != nil {
+		return 0, err
+	}
+
+	var removed []Tip
+	for _, id := range ids {
+		if tip := current.findTip(id); tip != nil {
+			removed = append(removed, *tip)
+		}
+	}
+
+	n, err := s.TipStore.Remove(ids...)
+	if err != nil {
+		return n, err
+	}
+	return n, s.record(opRemove, removed, nil)
+}
+
+func (s *journaledStore) Clear() error {
+	unlock, err := s.journal.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := s.TipStore.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := s.TipStore.Clear(); err != nil {
+		return err
+	}
+	return s.record(opClear, current.Tips, nil)
+}
+
+// Save isn't journaled, but takes the lock so it can't land between the
+// read and the write of a journaled operation.
+func (s *journaledStore) Save(tipsData *TipsData) error {
+	unlock, err := s.journal.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.TipStore.Save(tipsData)
+}
+
+func (s *journaledStore) record(op string, tips, before []Tip) error {
+	if len(tips) == 0 {
+		return nil
+	}
+	if err := s.journal.append(journalEntry{Op: op, Tips: tips, Before: before}); err != nil {
+		return err
+	}
+	return s.journal.compact()
+}
+
+// withReviewState returns tip with the review state (known mark, schedule
+// and rating) of state.
+func withReviewState(tip, state Tip) Tip {
+	tip.KnownAt = state.KnownAt
+	tip.Ease, tip.IntervalDays, tip.DueAt, tip.Reviews = state.Ease, state.IntervalDays, state.DueAt, state.Reviews
+	tip.Rating = state.Rating
+	return tip
+}
+
+func onlyReviewStateChanged(before, after Tip) bool {
+	return reflect.DeepEqual(withReviewState(before, after), after)
+}
+
+// Undo reverts the most recent operation that hasn't been undone yet.
+func (s *journaledStore) Undo() (journalEntry, error) {
+	unlock, err := s.journal.lock()
+	if err != nil {
+		return journalEntry{}, err
+	}
+	defer unlock()
+
+	undo, _, err := s.journal.stacks()
+	if err != nil {
+		return journalEntry{}, err
+	}
+	if len(undo) == 0 {
+		return journalEntry{}, errNothingToUndo
+	}
+
+	entry := undo[len(undo)-1]
+	switch entry.Op {
+	case opAdd:
+		_, err = s.TipStore.Remove(tipIDs(entry.Tips)...)
+	case opRemove, opClear:
+		err = s.restore(entry.Tips)
+	case opEdit:
+		err = s.revert(entry.Before)
+	default:
+		err = fmt.Errorf("unknown journal operation: %s", entry.Op)
+	}
+	if err != nil {
+		return journalEntry{}, err
+	}
+
+	return entry, s.journal.append(journalEntry{Op: opUndo, Target: entry.ID})
+}
+
+// Redo reapplies the most recently undone operation.
+func (s *journaledStore) Redo() (journalEntry, error) {
+	unlock, err := s.journal.lock()
+	if err != nil {
+		return journalEntry{}, err
+	}
+	defer unlock()
+
+	_, redo, err := s.journal.stacks()
+	if err != nil {
+		return journalEntry{}, err
+	}
+	if len(redo) == 0 {
+		return journalEntry{}, errNothingToRedo
+	}
+
+	entry := redo[len(redo)-1]
+	switch entry.Op {
+	case opAdd:
+		err = s.restore(entry.Tips)
+	case opRemove, opClear:
+		_, err = s.TipStore.Remove(tipIDs(entry.Tips)...)
+	case opEdit:
+		err = s.revert(entry.Tips)
+	default:
+		err = fmt.Errorf("unknown journal operation: %s", entry.Op)
+	}
+	if err != nil {
+		return journalEntry{}, err
+	}
+
+	return entry, s.journal.append(journalEntry{Op: opRedo, Target: entry.ID})
+}
+
+// restore adds tips back, skipping any that are already present.
+func (s *journaledStore) restore(tips []Tip) error {
+	current, err := s.TipStore.Load()
+	if err != nil {
+		return err
+	}
+
+	var missing []Tip
+	for _, tip := range tips {
+		if current.findTip(tip.ID) == nil {
+			missing = append(missing, tip)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return s.TipStore.Add(missing...)
+}
+
+// revert puts back the given versions of tips that still exist, keeping the
+// review state they've gained since.
+func (s *journaledStore) revert(tips []Tip) error {
+	current, err := s.TipStore.Load()
+	if err != nil {
+		return err
+	}
+
+	var reverted []Tip
+	for _, tip := range tips {
+		if existing := current.findTip(tip.ID); existing != nil {
+			reverted = append(reverted, withReviewState(tip, *existing))
+		}
+	}
+	if len(reverted) == 0 {
+		return nil
+	}
+	return s.TipStore.Update(reverted...)
+}
+
+func tipIDs(tips []Tip) []string {
+	ids := make([]string, len(tips))
+	for i, tip := range tips {
+		ids[i] = tip.ID
+	}
+	return ids
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last add, remove, clear or edit",
+	Long: `Undo the most recent change to the tips store.
+
+Every add, remove, clear and edit is recorded in a journal next to the tips
+file, so changes can be undone and redone in order. Grades, ratings and known
+marks aren't, and the journal keeps the last 1000 changes. Encrypted tips
+files don't keep a journal.`,
+	Args: cobra.NoArgs,
+	Run:  func(cmd *cobra.Command, args []string) { replayJournal(true) },
+}
+
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Redo the last undone change",
+	Args:  cobra.NoArgs,
+	Run:   func(cmd *cobra.Command, args []string) { replayJournal(false) },
+}
+
+func replayJournal(undo bool) {
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	journaled, ok := store.(*journaledStore)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Undo history is not available for this store\n")
+		os.Exit(1)
+	}
+
+	action, verb, replay := "undo", "Undid", journaled.Undo
+	if !undo {
+		action, verb, replay = "redo", "Redid", journaled.Redo
+	}
+
+	entry, err := replay()
+	if errors.Is(err, errNothingToUndo) || errors.Is(err, errNothingToRedo) {
+		fmt.Printf("Nothing to %s\n", action)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error replaying journal: %v\n", err)
+		os.Exit(1)
+	}
+
+	noun := "tips"
+	if len(entry.Tips) == 1 {
+		noun = "tip"
+	}
+	fmt.Printf("%s %s of %d %s from %s\n", verb, entry.Op, len(entry.Tips), noun, entry.Time.Local().Format("2006-01-02 15:04"))
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestJournaledStore(t *testing.T) *journaledStore {
+	t.Helper()
+
+	dir := t.TempDir()
+	return newJournaledStore(newJSONFileStore(filepath.Join(dir, "tips.json")), filepath.Join(dir, "tips.json.journal"))
+}
+
+func storedIDs(t *testing.T, store TipStore) string {
+	t.Helper()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	var ids []string
+	for _, tip := range tipsData.Tips {
+		id := tip.ID
+		if len(tip.Tags) > 0 {
+			id += "+"
+		}
+		if tip.isKnown() {
+			id += "*"
+		}
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return strings.Join(ids, ",")
+}
+
+func TestJournaledStore_UndoRedo(t *testing.T) {
+	store := newTestJournaledStore(t)
+
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "one"}, Tip{ID: "2", Topic: "git", Content: "two"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := store.Remove("1"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if err := store.Update(Tip{ID: "2", Topic: "git", Content: "two", Tags: []string{"stash"}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+
+	steps := []struct {
+		undo     bool
+		op       string
+		expected string
+	}{
+		{undo: true, op: opClear, expected: "2+"},
+		{undo: true, op: opEdit, expected: "2"},
+		{undo: true, op: opRemove, expected: "1,2"},
+		{undo: false, op: opRemove, expected: "2"},
+		{undo: false, op: opEdit, expected: "2+"},
+		{undo: false, op: opClear, expected: ""},
+		{undo: true, op: opClear, expected: "2+"},
+		{undo: true, op: opEdit, expected: "2"},
+		{undo: true, op: opRemove, expected: "1,2"},
+		{undo: true, op: opAdd, expected: ""},
+	}
+
+	for i, step := range steps {
+		replay := store.Redo
+		if step.undo {
+			replay = store.Undo
+		}
+
+		entry, err := replay()
+		if err != nil {
+			t.Fatalf("Step %d failed: %v", i, err)
+		}
+		if entry.Op != step.op {
+			t.Errorf("Step %d: expected %s, got %s", i, step.op, entry.Op)
+		}
+		if ids := storedIDs(t, store); ids != step.expected {
+			t.Errorf("Step %d: expected tips %q, got %q", i, step.expected, ids)
+		}
+	}
+
+	if _, err := store.Undo(); !errors.Is(err, errNothingToUndo) {
+		t.Errorf("Expected nothing to undo, got %v", err)
+	}
+}
+
+func TestJournaledStore_NewOperationClearsRedo(t *testing.T) {
+	store := newTestJournaledStore(t)
+
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "one"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := store.Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if err := store.Add(Tip{ID: "2", Topic: "git", Content: "two"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if _, err := store.Redo(); !errors.Is(err, errNothingToRedo) {
+		t.Errorf("Expected nothing to redo, got %v", err)
+	}
+	if ids := storedIDs(t, store); ids != "2" {
+		t.Errorf("Expected tips 2, got %q", ids)
+	}
+}
+
+func TestJournaledStore_UndoSkipsReviewState(t *testing.T) {
+	store := newTestJournaledStore(t)
+	now := time.Now()
+
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "one"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := store.Update(Tip{ID: "1", Topic: "git", Content: "one", Tags: []string{"stash"}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := store.Update(Tip{ID: "1", Topic: "git", Content: "one", Tags: []string{"stash"}, KnownAt: &now, Rating: 1}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	entry, err := store.Undo()
+	if err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if entry.Op != opEdit {
+		t.Errorf("Expected the edit to be undone, got %s", entry.Op)
+	}
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if tip := tipsData.findTip("1"); tip == nil || len(tip.Tags) != 0 || !tip.isKnown() || tip.Rating != 1 {
+		t.Errorf("Expected the tags reverted and the review state kept, got %+v", tip)
+	}
+}
+
+func TestJournal_Compact(t *testing.T) {
+	store := newJournaledStore(newMemoryStore(), filepath.Join(t.TempDir(), "tips.json.journal"))
+
+	for i := 0; i <= maxJournalEntries; i++ {
+		if err := store.Add(Tip{ID: fmt.Sprint(i), Topic: "git", Content: "tip"}); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if i == 0 {
+			if _, err := store.Undo(); err != nil {
+				t.Fatalf("Undo failed: %v", err)
+			}
+		}
+	}
+
+	entries, err := store.journal.entries()
+	if err != nil {
+		t.Fatalf("entries failed: %v", err)
+	}
+	if len(entries) != maxJournalEntries {
+		t.Fatalf("Expected %d entries, got %d", maxJournalEntries, len(entries))
+	}
+	if entries[0].Tips[0].ID != "1" || entries[len(entries)-1].Tips[0].ID != fmt.Sprint(maxJournalEntries) {
+		t.Errorf("Expected the most recent adds to be kept, got %s to %s", entries[0].Tips[0].ID, entries[len(entries)-1].Tips[0].ID)
+	}
+}
+
+// pausingStore runs pause before its first Update reaches the store.
+type pausingStore struct {
+	TipStore
+	pause func()
+}
+
+func (s *pausingStore) Update(tips ...Tip) error {
+	if pause := s.pause; pause != nil {
+		s.pause = nil
+		pause()
+	}
+	return s.TipStore.Update(tips...)
+}
+
+func TestJournaledStore_ConcurrentUpdates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.json")
+	if err := newJSONFileStore(path).Add(Tip{ID: "1", Topic: "git", Content: "v0"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	// Another process edits the tip while this one is between reading the
+	// tip and writing it.
+	other := newJournaledStore(newJSONFileStore(path), journalPath(path))
+	done := make(chan struct{})
+	paused := &pausingStore{TipStore: newJSONFileStore(path), pause: func() {
+		go func() {
+			defer close(done)
+			if err := other.Update(Tip{ID: "1", Topic: "git", Content: "v2"}); err != nil {
+				t.Errorf("Update failed: %v", err)
+			}
+		}()
+		time.Sleep(50 * time.Millisecond)
+	}}
+	store := newJournaledStore(paused, journalPath(path))
+	if err := store.Update(Tip{ID: "1", Topic: "git", Content: "v1"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	<-done
+
+	entries, err := store.journal.entries()
+	if err != nil {
+		t.Fatalf("entries failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 edits, got %d", len(entries))
+	}
+	previous := "v0"
+	for _, entry := range entries {
+		if entry.Before[0].Content != previous {
+			t.Errorf("Expected an edit of %s, got one of %s", previous, entry.Before[0].Content)
+		}
+		previous = entry.Tips[0].Content
+	}
+}
+
+func TestReplayJournal_Output(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	t.Setenv("TIPS_FILE", filepath.Join(t.TempDir(), "tips.json"))
+
+	store, err := openStore()
+	if err != nil {
+		t.Fatalf("openStore failed: %v", err)
+	}
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "one"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	output := captureStdout(t, func() { replayJournal(true) })
+	if !strings.HasPrefix(output, "Undid add of 1 tip from ") {
+		t.Errorf("Expected a singular undo message, got '%s'", output)
+	}
+}
+
+func TestJournal_SkipsTornLines(t *testing.T) {
+	store := newTestJournaledStore(t)
+
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "one"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	f, err := os.OpenFile(store.journal.path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	f.WriteString(`{"id": "torn", "op": "cle`)
+	f.Close()
+
+	entries, err := store.journal.entries()
+	if err != nil {
+		t.Fatalf("entries failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Op != opAdd {
+		t.Errorf("Expected a single add entry, got %+v", entries)
+	}
+}
+
+func TestOpenStore_Journal(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	store, err := openStore()
+	if err != nil {
+		t.Fatalf("openStore failed: %v", err)
+	}
+	if _, ok := store.(*journaledStore); !ok {
+		t.Errorf("Expected a journaled store, got %T", store)
+	}
+
+	t.Setenv("TIPS_PASSPHRASE", "secret")
+	path, err := getTipsFilePath()
+	if err != nil {
+		t.Fatalf("getTipsFilePath failed: %v", err)
+	}
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "one"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := setTipsFileEncryption(path, true); err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if _, err := os.Stat(journalPath(path)); !os.IsNotExist(err) {
+		t.Errorf("Expected journal to be removed when encrypting, got %v", err)
+	}
+
+	store, err = openStore()
+	if err != nil {
+		t.Fatalf("openStore failed: %v", err)
+	}
+	if _, ok := store.(*journaledStore); ok {
+		t.Error("Encrypted tips files should not be journaled")
+	}
+}
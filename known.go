@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+var knownCmd = &cobra.Command{
+	Use:   "known",
+	Short: "Manage tips marked as known",
+	Long: `Manage tips marked as known.
+
+Pressing 'k' while viewing tips archives the tip instead of deleting it, so it
+is no longer shown but can be listed and restored later.`,
+}
+
+var knownListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tips marked as known",
+	Long:  `List tips marked as known, most recently known first. Filter with --topic, --tag and --not-tag.`,
+	Args:  cobra.NoArgs,
+	Run:   listKnownTips,
+}
+
+var knownRestoreCmd = &cobra.Command{
+	Use:   "restore <id>...",
+	Short: "Show known tips again",
+	Args:  cobra.MinimumNArgs(1),
+	Run:   restoreKnownTips,
+}
+
+func (td *TipsData) knownTips(filter tipFilter) []Tip {
+	matches := filter.matcher()
+
+	var known []Tip
+	for _, tip := range td.Tips {
+		if tip.isKnown() && matches(&tip) {
+			known = append(known, tip)
+		}
+	}
+
+	sort.SliceStable(known, func(i, j int) bool {
+		return known[i].KnownAt.After(*known[j].KnownAt)
+	})
+	return known
+}
+
+func listKnownTips(cmd *cobra.Command, args []string) {
+	tipsData, err := loadTips()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	known := tipsData.knownTips(flagFilter())
+	if len(known) == 0 {
+		fmt.Println("No known tips")
+		return
+	}
+
+	for _, tip := range known {
+		fmt.Printf("%s [%s] known %s\n  %s\n", tip.ID, tip.Topic, tip.KnownAt.Local().Format("2006-01-02"), tip.Content)
+	}
+}
+
+func restoreKnownTips(cmd *cobra.Command, args []string) {
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	var restored []Tip
+	for _, id := range args {
+		tip, err := tipsData.resolveTip(id)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !tip.isKnown() {
+			fmt.Fprintf(os.Stderr, "Warning: Tip %s is not marked as known, skipping\n", tip.ID)
+			continue
+		}
+		tip.KnownAt = nil
+		restored = append(restored, *tip)
+	}
+
+	if len(restored) == 0 {
+		return
+	}
+
+	if err := store.Update(restored...); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving tips: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Successfully restored %d tips\n", len(restored))
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestTipsData_knownTips(t *testing.T) {
+	older := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(24 * time.Hour)
+
+	td := &TipsData{Tips: []Tip{
+		{ID: "1", Topic: "git", Content: "older", KnownAt: &older},
+		{ID: "2", Topic: "git", Content: "unknown"},
+		{ID: "3", Topic: "vim", Content: "newer", KnownAt: &newer},
+	}}
+
+	known := td.knownTips(tipFilter{})
+	if len(known) != 2 || known[0].ID != "3" || known[1].ID != "1" {
+		t.Errorf("Expected known tips 3,1, got %+v", known)
+	}
+
+	known = td.knownTips(tipFilter{topics: []string{"git"}})
+	if len(known) != 1 || known[0].ID != "1" {
+		t.Errorf("Expected known git tip 1, got %+v", known)
+	}
+}
+
+func TestKnownListAndRestore(t *testing.T) {
+	originalTopics := topicFlag
+	topicFlag = []string{}
+	defer func() { topicFlag = originalTopics }()
+
+	knownAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	store := useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "git stash pop", KnownAt: &knownAt},
+		Tip{ID: "2", Topic: "vim", Content: "dd deletes a line"},
+	)
+
+	output := captureStdout(t, func() {
+		listKnownTips(&cobra.Command{}, []string{})
+	})
+	if !strings.Contains(output, "1 [git] known 2024-03-01") || strings.Contains(output, "dd deletes a line") {
+		t.Errorf("Expected only known tip 1 to be listed, got '%s'", output)
+	}
+
+	output = captureStdout(t, func() {
+		restoreKnownTips(&cobra.Command{}, []string{"1"})
+	})
+	if !strings.Contains(output, "Successfully restored 1 tips") {
+		t.Errorf("Expected restore message, got '%s'", output)
+	}
+
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	if tip := tipsData.findTip("1"); tip == nil || tip.isKnown() {
+		t.Errorf("Expected tip 1 to be restored, got %+v", tip)
+	}
+
+	output = captureStdout(t, func() {
+		listKnownTips(&cobra.Command{}, []string{})
+	})
+	if !strings.Contains(output, "No known tips") {
+		t.Errorf("Expected no known tips, got '%s'", output)
+	}
+}
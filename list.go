@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	listKnownFlag   bool
+	listAllFlag     bool
+	listSinceFlag   string
+	listUntilFlag   string
+	listSortFlag    string
+	listReverseFlag bool
+	listLimitFlag   int
+	listOffsetFlag  int
+	listFormatFlag  string
+)
+
+// listContentWidth is how much of a tip's content the table shows.
+const listContentWidth = 60
+
+var (
+	listSortKeys = []string{"created", "topic", "due", "rating"}
+	listFormats  = []string{"table", "plain", "json"}
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stored tips",
+	Long: `List stored tips with their IDs, topics and creation dates.
+
+Tips marked as known are hidden unless --known (only known tips) or --all is
+given. Filter with --topic, --tag and --not-tag, and by creation date with
+--since and --until (YYYY-MM-DD, both inclusive).
+
+Sort keys:
+  created  Oldest first (default)
+  topic    By topic, then oldest first
+  due      Next spaced repetition review first, never reviewed tips last
+  rating   Highest rated first
+
+Output formats:
+  table    Aligned columns with truncated content (default)
+  plain    One tab-separated line per tip: id, topic, created, content
+  json     A JSON array of tips, for piping into other tools`,
+	Args: cobra.NoArgs,
+	Run:  listTips,
+}
+
+// listOptions selects, orders and pages tips for 'tips list'. The zero value
+// lists every tip that isn't known, oldest first.
+type listOptions struct {
+	filter  tipFilter
+	known   bool
+	all     bool
+	since   time.Time
+	until   time.Time
+	sortBy  string
+	reverse bool
+	limit   int
+	offset  int
+}
+
+func listOptionsFromFlags(cmd *cobra.Command) (listOptions, error) {
+	opts := listOptions{
+		filter:  commandFilter(cmd),
+		known:   listKnownFlag,
+		all:     listAllFlag,
+		sortBy:  listSortFlag,
+		reverse: listReverseFlag,
+		limit:   listLimitFlag,
+		offset:  listOffsetFlag,
+	}
+
+	if !slices.Contains(listSortKeys, opts.sortBy) {
+		return opts, fmt.Errorf("invalid sort key %q, expected one of: %s", opts.sortBy, strings.Join(listSortKeys, ", "))
+	}
+	if opts.limit < 0 || opts.offset < 0 {
+		return opts, fmt.Errorf("limit and offset must not be negative")
+	}
+
+	var err error
+	if listSinceFlag != "" {
+		if opts.since, err = time.ParseInLocation(dateFormat, listSinceFlag, time.Local); err != nil {
+			return opts, fmt.Errorf("invalid --since date %q, expected YYYY-MM-DD", listSinceFlag)
+		}
+	}
+	if listUntilFlag != "" {
+		if opts.until, err = time.ParseInLocation(dateFormat, listUntilFlag, time.Local); err != nil {
+			return opts, fmt.Errorf("invalid --until date %q, expected YYYY-MM-DD", listUntilFlag)
+		}
+		opts.until = opts.until.AddDate(0, 0, 1)
+	}
+	return opts, nil
+}
+
+// listTips returns the page of matching tips and the number of tips that
+// matched before paging.
+func (td *TipsData) listTips(opts listOptions) ([]Tip, int) {
+	matches := opts.filter.matcher()
+
+	var tips []Tip
+	for _, tip := range td.Tips {
+		if !opts.all && tip.isKnown() != opts.known {
+			continue
+		}
+		if !opts.since.IsZero() && tip.CreatedAt.Before(opts.since) {
+			continue
+		}
+		if !opts.until.IsZero() && !tip.CreatedAt.Before(opts.until) {
+			continue
+		}
+		if matches(&tip) {
+			tips = append(tips, tip)
+		}
+	}
+
+	less := listLess(opts.sortBy)
+	sort.SliceStable(tips, func(i, j int) bool {
+		if opts.reverse {
+			return less(&tips[j], &tips[i])
+		}
+		return less(&tips[i], &tips[j])
+	})
+
+	total := len(tips)
+	tips = tips[min(opts.offset, total):]
+	if opts.limit > 0 && opts.limit < len(tips) {
+		tips = tips[:opts.limit]
+	}
+	return tips, total
+}
+
+func listLess(sortBy string) func(a, b *Tip) bool {
+	byCreated := func(a, b *Tip) bool { return a.CreatedAt.Before(b.CreatedAt) }
+
+	switch sortBy {
+	case "topic":
+		return func(a, b *Tip) bool {
+			if at, bt := strings.ToLower(a.Topic), strings.ToLower(b.Topic); at != bt {
+				return at < bt
+			}
+			return byCreated(a, b)
+		}
+	case "due":
+		return func(a, b *Tip) bool {
+			if (a.DueAt == nil) != (b.DueAt == nil) {
+				return b.DueAt == nil
+			}
+			if a.DueAt != nil && !a.DueAt.Equal(*b.DueAt) {
+				return a.DueAt.Before(*b.DueAt)
+			}
+			return byCreated(a, b)
+		}
+	case "rating":
+		return func(a, b *Tip) bool {
+			if a.Rating != b.Rating {
+				return a.Rating > b.Rating
+			}
+			return byCreated(a, b)
+		}
+	default:
+		return byCreated
+	}
+}
+
+func listTips(cmd *cobra.Command, args []string) {
+	if !slices.Contains(listFormats, listFormatFlag) {
+		fmt.Fprintf(os.Stderr, "Error: Invalid format %q, expected one of: %s\n", listFormatFlag, strings.Join(listFormats, ", "))
+		os.Exit(1)
+	}
+	opts, err := listOptionsFromFlags(cmd)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	tipsData, err := loadTipsByTopic(opts.filter.topics)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	tips, total := tipsData.listTips(opts)
+
+	switch listFormatFlag {
+	case "json":
+		if tips == nil {
+			tips = []Tip{}
+		}
+		data, err := json.MarshalIndent(tips, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error encoding tips: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Println(string(data))
+
+	case "plain":
+		for _, tip := range tips {
+			fmt.Printf("%s\t%s\t%s\t%s\n", tip.ID, tip.Topic, tip.CreatedAt.Local().Format(dateFormat), oneLine(tip.Content))
+		}
+
+	default:
+		if len(tips) == 0 {
+			fmt.Println("No tips found")
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTOPIC\tCREATED\tCONTENT")
+		for _, tip := range tips {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", tip.ID, tip.Topic, tip.CreatedAt.Local().Format(dateFormat), truncate(oneLine(tip.Content), listContentWidth))
+		}
+		w.Flush()
+
+		if len(tips) < total {
+			fmt.Printf("\nShowing %d-%d of %d tips\n", opts.offset+1, opts.offset+len(tips), total)
+		}
+	}
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestListTips(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 12, 0, 0, 0, time.Local) }
+	due := day(20)
+	td := &TipsData{Tips: []Tip{
+		{ID: "1", Topic: "vim", CreatedAt: day(3), Rating: 1},
+		{ID: "2", Topic: "git", CreatedAt: day(1), DueAt: &due},
+		{ID: "3", Topic: "go/testing", CreatedAt: day(2), Tags: []string{"subtests"}},
+		{ID: "4", Topic: "git", CreatedAt: day(4), KnownAt: &due},
+		{ID: "5", Topic: "Go", CreatedAt: day(5), Rating: -1},
+	}}
+
+	tests := []struct {
+		name     string
+		opts     listOptions
+		expected string
+		total    int
+	}{
+		{name: "defaults", opts: listOptions{}, expected: "2,3,1,5", total: 4},
+		{name: "known only", opts: listOptions{known: true}, expected: "4", total: 1},
+		{name: "all", opts: listOptions{all: true}, expected: "2,3,1,4,5", total: 5},
+		{name: "topic", opts: listOptions{filter: tipFilter{topics: []string{"go"}}}, expected: "3,5", total: 2},
+		{name: "tag", opts: listOptions{filter: tipFilter{tags: []string{"subtests"}}}, expected: "3", total: 1},
+		{name: "date range", opts: listOptions{since: day(2), until: day(4)}, expected: "3,1", total: 2},
+		{name: "by topic", opts: listOptions{sortBy: "topic"}, expected: "2,5,3,1", total: 4},
+		{name: "by due", opts: listOptions{sortBy: "due"}, expected: "2,3,1,5", total: 4},
+		{name: "by rating", opts: listOptions{sortBy: "rating"}, expected: "1,2,3,5", total: 4},
+		{name: "reversed", opts: listOptions{reverse: true}, expected: "5,1,3,2", total: 4},
+		{name: "paged", opts: listOptions{limit: 2, offset: 1}, expected: "3,1", total: 4},
+		{name: "offset past the end", opts: listOptions{offset: 10}, expected: "", total: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tips, total := td.listTips(tt.opts)
+			ids := make([]string, len(tips))
+			for i, tip := range tips {
+				ids[i] = tip.ID
+			}
+			if got := strings.Join(ids, ","); got != tt.expected || total != tt.total {
+				t.Errorf("Expected %q of %d, got %q of %d", tt.expected, tt.total, got, total)
+			}
+		})
+	}
+}
+
+func TestListTipsCommand(t *testing.T) {
+	originalTopics, originalSort, originalFormat, originalLimit := topicFlag, listSortFlag, listFormatFlag, listLimitFlag
+	defer func() {
+		topicFlag, listSortFlag, listFormatFlag, listLimitFlag = originalTopics, originalSort, originalFormat, originalLimit
+	}()
+	topicFlag, listSortFlag, listLimitFlag = []string{}, "created", 1
+
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "git stash pop", CreatedAt: time.Now().Add(-time.Hour)},
+		Tip{ID: "2", Topic: "vim", Content: "dd\ndeletes a line", CreatedAt: time.Now()},
+	)
+
+	listFormatFlag = "table"
+	output := captureStdout(t, func() { listTips(&cobra.Command{}, nil) })
+	if !strings.HasPrefix(output, "ID") || !strings.Contains(output, "git stash pop") || !strings.Contains(output, "Showing 1-1 of 2 tips") {
+		t.Errorf("Expected a table with the first tip and a page summary, got '%s'", output)
+	}
+
+	listFormatFlag, listLimitFlag = "plain", 0
+	output = captureStdout(t, func() { listTips(&cobra.Command{}, nil) })
+	if lines := strings.Split(strings.TrimSpace(output), "\n"); len(lines) != 2 || !strings.HasSuffix(lines[1], "\tdd deletes a line") {
+		t.Errorf("Expected one tab-separated line per tip, got '%s'", output)
+	}
+
+	// A default topic from the config file doesn't hide other tips.
+	topicFlag = []string{"git"}
+	output = captureStdout(t, func() { listTips(&cobra.Command{}, nil) })
+	if !strings.Contains(output, "dd deletes a line") {
+		t.Errorf("Expected default topics to be ignored, got '%s'", output)
+	}
+
+	listFormatFlag = "json"
+	cmd := topicCommand(t, "rust")
+	output = captureStdout(t, func() { listTips(cmd, nil) })
+	if strings.TrimSpace(output) != "[]" {
+		t.Errorf("Expected an empty JSON array, got '%s'", output)
+	}
+}
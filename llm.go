@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/tmc/langchaingo/llms"
@@ -14,19 +15,42 @@ import (
 )
 
 type TipResponse struct {
-	Content string `json:"content"`
+	Content string   `json:"content"`
+	Tags    []string `json:"tags,omitempty"`
 }
 
 type TipsResponse struct {
 	Tips []TipResponse `json:"tips"`
 }
 
-func createLLM(ctx context.Context) (llms.Model, error) {
-	model := os.Getenv("TIPS_MODEL")
-	if model == "" {
-		model = "openai/gpt-4o"
+const defaultModel = "openai/gpt-4o"
+
+func getModel() (string, error) {
+	if modelFlag != "" {
+		return modelFlag, nil
+	}
+	if model := os.Getenv("TIPS_MODEL"); model != "" {
+		return model, nil
+	}
+	if profileModel != "" {
+		return profileModel, nil
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	if config.Model != "" {
+		return config.Model, nil
 	}
+	return defaultModel, nil
+}
 
+func createLLM(ctx context.Context) (llms.Model, error) {
+	model, err := getModel()
+	if err != nil {
+		return nil, err
+	}
 	parts := strings.Split(model, "/")
 	if len(parts) != 2 {
 		return nil, fmt.Errorf("invalid model format. Expected 'provider/model' (e.g., 'openai/gpt-4o')")
@@ -58,15 +82,19 @@ func createLLM(ctx context.Context) (llms.Model, error) {
 	}
 }
 
-func generateTips(topic string, count int) ([]TipResponse, error) {
-	ctx := context.Background()
+const defaultPromptStyle = "cheatsheet"
 
-	llm, err := createLLM(ctx)
-	if err != nil {
-		return nil, err
-	}
+const promptResponseFormat = `IMPORTANT: Return ONLY a valid JSON object. Do not wrap it in markdown code blocks or add any other text. Use this exact format:
+{
+  "tips": [
+    {"content": "tip 1 content here", "tags": ["keyword", "another-keyword"]},
+    {"content": "tip 2 content here", "tags": ["keyword"]}
+  ]
+}
+Tag each tip with 1-3 short lowercase keywords naming the command, feature or concept it covers.`
 
-	prompt := fmt.Sprintf(`Generate %d concise cheatsheet-style tips about %s. Each tip should be:
+var promptStyles = map[string]string{
+	"cheatsheet": `Generate %d concise cheatsheet-style tips about %s. Each tip should be:
 - Brief and to-the-point (1-2 sentences max)
 - Include specific commands, shortcuts, or code snippets when applicable
 - Focus on practical, immediately usable information
@@ -77,15 +105,76 @@ Examples of good cheatsheet tips:
 - 'vim: Delete entire line with dd, copy line with yy, paste with p'
 - 'bash: Use !! to repeat last command, !$ for last argument of previous command'
 
-IMPORTANT: Return ONLY a valid JSON object. Do not wrap it in markdown code blocks or add any other text. Use this exact format:
-{
-  "tips": [
-    {"content": "tip 1 content here"},
-    {"content": "tip 2 content here"}
-  ]
+` + promptResponseFormat + `
+
+Generate %d tips about %s in this cheatsheet style.`,
+	"detailed": `Generate %d detailed tips about %s. Each tip should be:
+- 2-4 sentences long
+- Explain what the technique does and when it is useful
+- Include specific commands, shortcuts, or code snippets when applicable
+- Mention common pitfalls or useful variations where relevant
+
+Examples of good detailed tips:
+- 'git stash: Use git stash to shelve uncommitted changes when you need to switch branches quickly. Restore them with git stash pop, or git stash apply to keep a copy in the stash. Add -u to include untracked files.'
+- 'bash: !! repeats the last command, which is handy as sudo !! after a permission error. !$ expands to the last argument of the previous command, so mkdir dir && cd !$ works as expected.'
+
+` + promptResponseFormat + `
+
+Generate %d tips about %s in this detailed style.`,
 }
 
-Generate %d tips about %s in this cheatsheet style.`, count, topic, count, topic)
+func promptStyleNames() []string {
+	names := make([]string, 0, len(promptStyles))
+	for name := range promptStyles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func getPromptStyle() (string, error) {
+	if promptStyleFlag != "" {
+		return promptStyleFlag, nil
+	}
+	if style := os.Getenv("TIPS_PROMPT_STYLE"); style != "" {
+		return style, nil
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	if config.PromptStyle != "" {
+		return config.PromptStyle, nil
+	}
+	return defaultPromptStyle, nil
+}
+
+func buildPrompt(style, topic string, count int) (string, error) {
+	template, ok := promptStyles[style]
+	if !ok {
+		return "", fmt.Errorf("unsupported prompt style: %s. Supported styles: %s", style, strings.Join(promptStyleNames(), ", "))
+	}
+	return fmt.Sprintf(template, count, topic, count, topic), nil
+}
+
+func generateTips(topic string, count int) ([]TipResponse, error) {
+	ctx := context.Background()
+
+	style, err := getPromptStyle()
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, err := buildPrompt(style, topic, count)
+	if err != nil {
+		return nil, err
+	}
+
+	llm, err := createLLM(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := llms.GenerateFromSinglePrompt(ctx, llm, prompt)
 	if err != nil {
@@ -98,6 +187,12 @@ Generate %d tips about %s in this cheatsheet style.`, count, topic, count, topic
 		return nil, fmt.Errorf("failed to generate content: %w", err)
 	}
 
+	return parseTipsResponse(resp)
+}
+
+// parseTipsResponse reads the model's JSON reply. Tags are optional: tips
+// without them are kept untagged, and the rest are normalized.
+func parseTipsResponse(resp string) ([]TipResponse, error) {
 	cleanResp := strings.TrimSpace(resp)
 	if strings.HasPrefix(cleanResp, "```json") || strings.HasPrefix(cleanResp, "```") {
 		cleanResp = strings.TrimPrefix(cleanResp, "```json")
@@ -115,5 +210,8 @@ Generate %d tips about %s in this cheatsheet style.`, count, topic, count, topic
 		return nil, fmt.Errorf("no tips generated in response")
 	}
 
+	for i := range tipsResponse.Tips {
+		tipsResponse.Tips[i].Tags = normalizeTags(tipsResponse.Tips[i].Tags)
+	}
 	return tipsResponse.Tips, nil
 }
@@ -3,13 +3,14 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 )
 
 func TestCreateLLM(t *testing.T) {
+	setTestHome(t, t.TempDir())
 	ctx := context.Background()
 
 	originalModel := os.Getenv("TIPS_MODEL")
@@ -301,46 +302,62 @@ func TestPromptGeneration(t *testing.T) {
 	topic := "git"
 	count := 5
 
-	expectedPrompt := generatePromptString(topic, count)
+	for _, style := range []string{"cheatsheet", "detailed"} {
+		t.Run(style, func(t *testing.T) {
+			prompt, err := buildPrompt(style, topic, count)
+			if err != nil {
+				t.Fatalf("buildPrompt failed: %v", err)
+			}
 
-	if !strings.Contains(expectedPrompt, topic) {
-		t.Errorf("Prompt should contain topic '%s'", topic)
-	}
+			if !strings.Contains(prompt, topic) {
+				t.Errorf("Prompt should contain topic '%s'", topic)
+			}
 
-	if !strings.Contains(expectedPrompt, "5") {
-		t.Error("Prompt should contain count")
-	}
+			if !strings.Contains(prompt, "5") {
+				t.Error("Prompt should contain count")
+			}
+
+			if !strings.Contains(prompt, "JSON") {
+				t.Error("Prompt should mention JSON format")
+			}
 
-	if !strings.Contains(expectedPrompt, "JSON") {
-		t.Error("Prompt should mention JSON format")
+			if !strings.Contains(prompt, style) {
+				t.Errorf("Prompt should mention %s style", style)
+			}
+		})
 	}
 
-	if !strings.Contains(expectedPrompt, "cheatsheet") {
-		t.Error("Prompt should mention cheatsheet style")
+	if _, err := buildPrompt("haiku", topic, count); err == nil || !strings.Contains(err.Error(), "unsupported prompt style") {
+		t.Errorf("Expected unsupported prompt style error, got %v", err)
 	}
 }
 
-func generatePromptString(topic string, count int) string {
-	return fmt.Sprintf(`Generate %d concise cheatsheet-style tips about %s. Each tip should be:
-- Brief and to-the-point (1-2 sentences max)
-- Include specific commands, shortcuts, or code snippets when applicable
-- Focus on practical, immediately usable information
-- Written in a reference format like you'd find in a quick reference guide
-
-Examples of good cheatsheet tips:
-- 'git stash: Temporarily save uncommitted changes with git stash, restore with git stash pop'
-- 'vim: Delete entire line with dd, copy line with yy, paste with p'
-- 'bash: Use !! to repeat last command, !$ for last argument of previous command'
-
-IMPORTANT: Return ONLY a valid JSON object. Do not wrap it in markdown code blocks or add any other text. Use this exact format:
-{
-  "tips": [
-    {"content": "tip 1 content here"},
-    {"content": "tip 2 content here"}
-  ]
-}
+func TestGetPromptStyle(t *testing.T) {
+	setTestHome(t, t.TempDir())
+
+	assertPromptStyle := func(expected string) {
+		t.Helper()
+		style, err := getPromptStyle()
+		if err != nil {
+			t.Fatalf("getPromptStyle failed: %v", err)
+		}
+		if style != expected {
+			t.Errorf("Expected prompt style %s, got %s", expected, style)
+		}
+	}
+
+	assertPromptStyle(defaultPromptStyle)
 
-Generate %d tips about %s in this cheatsheet style.`, count, topic, count, topic)
+	writeTestConfig(t, `prompt_style = "detailed"`)
+	assertPromptStyle("detailed")
+
+	t.Setenv("TIPS_PROMPT_STYLE", "cheatsheet")
+	assertPromptStyle("cheatsheet")
+
+	original := promptStyleFlag
+	defer func() { promptStyleFlag = original }()
+	promptStyleFlag = "detailed"
+	assertPromptStyle("detailed")
 }
 
 func TestModelEnvironmentHandling(t *testing.T) {
@@ -367,3 +384,49 @@ func TestModelEnvironmentHandling(t *testing.T) {
 		t.Errorf("Expected custom model '%s', got '%s'", customModel, model)
 	}
 }
+
+func TestGetModel(t *testing.T) {
+	setTestHome(t, t.TempDir())
+
+	assertModel := func(expected string) {
+		t.Helper()
+		model, err := getModel()
+		if err != nil {
+			t.Fatalf("getModel failed: %v", err)
+		}
+		if model != expected {
+			t.Errorf("Expected model %s, got %s", expected, model)
+		}
+	}
+
+	assertModel(defaultModel)
+
+	writeTestConfig(t, `model = "google/gemini-pro"`)
+	assertModel("google/gemini-pro")
+
+	t.Setenv("TIPS_MODEL", "anthropic/claude-3")
+	assertModel("anthropic/claude-3")
+
+	modelFlag = "openai/gpt-4o-mini"
+	assertModel("openai/gpt-4o-mini")
+}
+
+func TestParseTipsResponse_Tags(t *testing.T) {
+	tips, err := parseTipsResponse(`{"tips": [
+		{"content": "tip 1", "tags": ["Git", "stash", "git"]},
+		{"content": "tip 2"}
+	]}`)
+	if err != nil {
+		t.Fatalf("parseTipsResponse failed: %v", err)
+	}
+
+	if len(tips) != 2 {
+		t.Fatalf("Expected 2 tips, got %d", len(tips))
+	}
+	if !reflect.DeepEqual(tips[0].Tags, []string{"git", "stash"}) {
+		t.Errorf("Expected normalized tags [git stash], got %v", tips[0].Tags)
+	}
+	if tips[1].Tags != nil {
+		t.Errorf("Expected no tags on tip 2, got %v", tips[1].Tags)
+	}
+}
@@ -12,8 +12,22 @@ const Version = "1.0.1"
 
 var (
 	topicFlag   []string
+	tagFlag     []string
+	notTagFlag  []string
 	refreshFlag int
 	countFlag   int
+	storeFlag   string
+	fileFlag    string
+	profileFlag string
+	modelFlag   string
+	shuffleFlag bool
+
+	strategyFlag string
+	seedFlag     int64
+	// seedSet records whether --seed was given, since any value is a valid seed.
+	seedSet bool
+
+	promptStyleFlag string
 )
 
 var rootCmd = &cobra.Command{
@@ -24,6 +38,17 @@ var rootCmd = &cobra.Command{
 
 It allows you to generate tips using LLM providers (OpenAI, Anthropic, Google),
 store them locally, and display them in an interactive terminal interface.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if err := applyConfig(cmd.Flags()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+			os.Exit(1)
+		}
+		if err := validateTopicPatterns(topicFlag); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		seedSet = cmd.Flags().Changed("seed")
+	},
 	Run: func(cmd *cobra.Command, args []string) { showCmd.Run(cmd, args) },
 }
 
@@ -32,7 +57,24 @@ var showCmd = &cobra.Command{
 	Short: "Display tips in interactive mode",
 	Long: `Display tips in an interactive terminal interface.
 
-Use 'n' to get next tip, 'k' to mark current tip as known, 'q' to quit.
+Use 'n' to get next tip, 'p' or left arrow to go back to the previous one,
+right arrow to go forward again, '/' to search (then 'n' for the next match
+and Esc to stop searching), 'k' to mark current tip as known, 'u' to undo
+the last 'k', 'q' to quit. Every tip shown is logged; see 'tips history'.
+Grade the current tip with '1' (again), '2' (hard), '3' (good) or '4' (easy)
+to schedule when it comes back. Overdue tips are shown first.
+Press '+' or '-' to rate the current tip up or down.
+
+Choose how tips are picked with --strategy:
+  srs       overdue tips first, then new ones (default)
+  random    any matching tip, uniformly
+  shuffle   every matching tip once before any repeats; the rotation carries
+            over between runs (--shuffle for short)
+  weighted  by topic_weights and age_half_life_days from the config file, and
+            by rating
+Pass --seed to make the selection reproducible. For a tip that is the same
+for everyone all day, see 'tips today'.
+Known tips are archived rather than deleted; see 'tips known'.
 Tips can be filtered by topic using the --topic flag.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if refreshFlag <= 0 {
@@ -56,16 +98,49 @@ Requires API key for your chosen provider:
 - Anthropic: Set ANTHROPIC_API_KEY environment variable  
 - Google: Set GOOGLE_API_KEY environment variable
 
-Set model with TIPS_MODEL (default: openai/gpt-4o)
+Set model with --model or TIPS_MODEL (default: openai/gpt-4o)
 Format: provider/model (e.g., anthropic/claude-3-sonnet-20240229)`,
 	Run: generateTipsForTopics,
 }
 
-var clearCmd = &cobra.Command{
-	Use:   "clear",
-	Short: "Delete all stored tips",
-	Long:  `Delete all tips from local storage (~/.tips.json).`,
-	Run:   clearAllTips,
+var storeCmd = &cobra.Command{
+	Use:   "store",
+	Short: "Manage the tips storage backend",
+	Long: `Manage the storage backend used for tips.
+
+Tips are stored in a JSON file ($XDG_DATA_HOME/tips/tips.json) by default. Select
+the SQLite backend (tips.db, next to the JSON file) with --store sqlite, the
+TIPS_STORE environment variable, or "store" in the config file.`,
+}
+
+var storeMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Copy tips from the JSON file into the SQLite database",
+	Long: `Copy all tips from the JSON file into the SQLite database next to it.
+
+Tips already present in the database are replaced, so the migration can be re-run safely.
+The JSON file is left untouched.`,
+	Run: migrateStore,
+}
+
+var storeEncryptCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the tips file",
+	Long: `Encrypt the tips file with AES-256-GCM, using a key derived with scrypt from
+TIPS_PASSPHRASE or the contents of a keyfile (TIPS_KEYFILE, or "keyfile" in the
+config file).
+
+Once encrypted, the file stays encrypted on every save and is decrypted
+transparently when the same passphrase or keyfile is available.`,
+	Args: cobra.NoArgs,
+	Run:  func(cmd *cobra.Command, args []string) { convertStoreEncryption(true) },
+}
+
+var storeDecryptCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt the tips file back to plain JSON",
+	Args:  cobra.NoArgs,
+	Run:   func(cmd *cobra.Command, args []string) { convertStoreEncryption(false) },
 }
 
 func generateTipsForTopics(cmd *cobra.Command, args []string) {
@@ -74,11 +149,29 @@ func generateTipsForTopics(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	existing, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
 	for _, topic := range topicFlag {
-		if topic = strings.TrimSpace(topic); topic == "" {
+		if strings.TrimSpace(topic) == "" {
 			fmt.Fprintf(os.Stderr, "Warning: Empty topic provided, skipping\n")
 			continue
 		}
+		topic, err := canonicalTopic(topic)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			continue
+		}
 
 		if countFlag <= 0 {
 			fmt.Fprintf(os.Stderr, "Error: Count must be greater than 0, got %d\n", countFlag)
@@ -93,51 +186,132 @@ func generateTipsForTopics(cmd *cobra.Command, args []string) {
 			continue
 		}
 
-		tipsData, err := loadTips()
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error loading existing tips: %v\n", err)
-			continue
-		}
-
+		var added []Tip
+		skipped := 0
 		for _, tip := range tips {
-			tipsData.addTip(topic, tip.Content)
+			newTip, err := existing.addTip(topic, tip.Content, tip.Tags...)
+			if err != nil {
+				skipped++
+				continue
+			}
+			newTip.Source = sourceLLM
+			added = append(added, *newTip)
 		}
 
-		if err := saveTips(tipsData); err != nil {
-			fmt.Fprintf(os.Stderr, "Error saving tips: %v\n", err)
-			continue
+		if len(added) > 0 {
+			if err := store.Add(added...); err != nil {
+				fmt.Fprintf(os.Stderr, "Error saving tips: %v\n", err)
+				continue
+			}
 		}
 
-		fmt.Printf("Successfully generated and saved %d tips for %s\n", len(tips), topic)
+		if skipped > 0 {
+			fmt.Printf("Successfully generated and saved %d tips for %s, skipping %d duplicate or invalid tips\n", len(added), topic, skipped)
+		} else {
+			fmt.Printf("Successfully generated and saved %d tips for %s\n", len(added), topic)
+		}
 	}
 }
 
-func clearAllTips(cmd *cobra.Command, args []string) {
-	filePath, err := getTipsFilePath()
+func migrateStore(cmd *cobra.Command, args []string) {
+	jsonPath, err := getTipsFilePath()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting tips file path: %v\n", err)
 		os.Exit(1)
 	}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		fmt.Println("No tips file found - nothing to clear")
-		return
+	dbPath, err := getTipsDBPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting tips database path: %v\n", err)
+		os.Exit(1)
 	}
 
-	if err := os.Remove(filePath); err != nil {
-		fmt.Fprintf(os.Stderr, "Error deleting tips file: %v\n", err)
+	migrated, err := migrateJSONToSQLite(jsonPath, dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error migrating tips: %v\n", err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("Successfully deleted tips file: %s\n", filePath)
+	fmt.Printf("Successfully migrated %d tips from %s to %s\n", migrated, jsonPath, dbPath)
+	fmt.Println("Use --store sqlite or set TIPS_STORE=sqlite to read tips from the database.")
+}
+
+func convertStoreEncryption(encrypt bool) {
+	kind, err := storeKind()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading config: %v\n", err)
+		os.Exit(1)
+	}
+	if kind != "json" {
+		fmt.Fprintf(os.Stderr, "Error: Encryption is only supported by the json store, not %s\n", kind)
+		os.Exit(1)
+	}
+
+	path, err := getTipsFilePath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting tips file path: %v\n", err)
+		os.Exit(1)
+	}
+
+	count, err := setTipsFileEncryption(path, encrypt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error converting tips file: %v\n", err)
+		os.Exit(1)
+	}
+
+	if encrypt {
+		fmt.Printf("Successfully encrypted %d tips in %s\n", count, path)
+	} else {
+		fmt.Printf("Successfully decrypted %d tips in %s\n", count, path)
+	}
 }
 
 func init() {
 	rootCmd.PersistentFlags().StringSliceVarP(&topicFlag, "topic", "t", []string{}, "Filter by topic (can specify multiple)")
+	rootCmd.PersistentFlags().StringSliceVar(&tagFlag, "tag", []string{}, "Only show tips with this tag (can specify multiple)")
+	rootCmd.PersistentFlags().StringSliceVar(&notTagFlag, "not-tag", []string{}, "Hide tips with this tag (can specify multiple)")
 	rootCmd.PersistentFlags().IntVarP(&refreshFlag, "refresh", "r", 60, "Refresh interval in minutes")
 	rootCmd.PersistentFlags().IntVarP(&countFlag, "count", "c", 20, "Number of tips to generate per API call")
+	rootCmd.PersistentFlags().StringVarP(&fileFlag, "file", "f", "", "Path to the tips file (default $XDG_DATA_HOME/tips/tips.json, or TIPS_FILE)")
+	rootCmd.PersistentFlags().StringVar(&storeFlag, "store", "", "Storage backend: json or sqlite (default json, or TIPS_STORE)")
+	rootCmd.PersistentFlags().StringVarP(&profileFlag, "profile", "p", "", "Profile to use (default the current profile, or TIPS_PROFILE)")
+	rootCmd.PersistentFlags().StringVar(&strategyFlag, "strategy", "", "Tip selection strategy: srs, random, shuffle or weighted (default srs, or TIPS_STRATEGY)")
+	rootCmd.PersistentFlags().Int64Var(&seedFlag, "seed", 0, "Seed tip selection so the same tips come up in the same order")
+	rootCmd.PersistentFlags().BoolVar(&shuffleFlag, "shuffle", false, "Show every matching tip once before repeating any (same as --strategy shuffle)")
+	rootCmd.PersistentFlags().StringVar(&modelFlag, "model", "", "Model to generate tips with, as provider/model (default openai/gpt-4o, or TIPS_MODEL)")
+
+	topicsCmd.Flags().BoolVar(&topicsTreeFlag, "tree", false, "Show topics as a hierarchy")
+	topicsMergeCmd.Flags().StringVar(&topicsIntoFlag, "into", "", "Topic to merge into")
+	clearCmd.Flags().StringVar(&clearOlderThanFlag, "older-than", "", "Only delete tips created longer ago than this, like 90d, 12w or 1y")
+	clearCmd.Flags().BoolVar(&clearKnownFlag, "known", false, "Only delete tips marked as known")
+	clearCmd.Flags().StringVar(&clearSourceFlag, "source", "", "Only delete tips from this source: llm or manual")
+	clearCmd.Flags().BoolVar(&clearDryRunFlag, "dry-run", false, "List the tips that would be deleted without deleting them")
+	clearCmd.Flags().BoolVarP(&clearYesFlag, "yes", "y", false, "Delete without asking for confirmation")
+	addCmd.Flags().BoolVarP(&addEditFlag, "edit", "e", false, "Write the tip in $EDITOR")
+	listCmd.Flags().BoolVar(&listKnownFlag, "known", false, "Only list tips marked as known")
+	listCmd.Flags().BoolVar(&listAllFlag, "all", false, "Include tips marked as known")
+	listCmd.Flags().StringVar(&listSinceFlag, "since", "", "Only list tips created on or after this date (YYYY-MM-DD)")
+	listCmd.Flags().StringVar(&listUntilFlag, "until", "", "Only list tips created on or before this date (YYYY-MM-DD)")
+	listCmd.Flags().StringVar(&listSortFlag, "sort", "created", "Sort by created, topic, due or rating")
+	listCmd.Flags().BoolVar(&listReverseFlag, "reverse", false, "Reverse the sort order")
+	listCmd.Flags().IntVarP(&listLimitFlag, "limit", "n", 0, "Maximum number of tips to list (default all)")
+	listCmd.Flags().IntVar(&listOffsetFlag, "offset", 0, "Number of tips to skip")
+	listCmd.Flags().StringVar(&listFormatFlag, "format", "table", "Output format: table, plain or json")
+	listCmd.MarkFlagsMutuallyExclusive("known", "all")
+	exportCmd.Flags().StringVar(&exportFormatFlag, "format", "", "Output format: md, csv, jsonl or html (default from the -o extension, or md)")
+	exportCmd.Flags().StringVarP(&exportOutputFlag, "output", "o", "", "File to write (default stdout)")
+	searchCmd.Flags().IntVarP(&searchLimitFlag, "limit", "n", 10, "Maximum number of results")
+	historyCmd.Flags().IntVarP(&historyLimitFlag, "limit", "n", 20, "Number of views to list")
+	todayCmd.Flags().StringVar(&dateFlag, "date", "", "Show the tip for another day (YYYY-MM-DD, default today in UTC)")
+	generateCmd.Flags().StringVar(&promptStyleFlag, "prompt-style", "", "Prompt style: cheatsheet or detailed (default cheatsheet, or TIPS_PROMPT_STYLE)")
 
-	rootCmd.AddCommand(showCmd, generateCmd, clearCmd)
+	storeCmd.AddCommand(storeMigrateCmd, storeEncryptCmd, storeDecryptCmd)
+	profileCmd.AddCommand(profileListCmd, profileCreateCmd, profileDeleteCmd, profileUseCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd, configPathCmd)
+	knownCmd.AddCommand(knownListCmd, knownRestoreCmd)
+	tagCmd.AddCommand(tagAddCmd, tagRemoveCmd)
+	topicsCmd.AddCommand(topicsRenameCmd, topicsMergeCmd)
+	rootCmd.AddCommand(showCmd, listCmd, searchCmd, todayCmd, historyCmd, generateCmd, addCmd, exportCmd, editCmd, rmCmd, topicsCmd, tagCmd, clearCmd, storeCmd, profileCmd, configCmd, knownCmd, undoCmd, redoCmd)
 }
 
 func main() {
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -46,6 +47,8 @@ func TestCommandFlags(t *testing.T) {
 }
 
 func TestGenerateTipsForTopics(t *testing.T) {
+	useMemoryStore(t)
+
 	originalTopicFlag := topicFlag
 	originalCountFlag := countFlag
 	defer func() {
@@ -152,67 +155,55 @@ func TestGenerateTipsValidation(t *testing.T) {
 }
 
 func TestClearAllTips(t *testing.T) {
-	t.Run("no file exists", func(t *testing.T) {
-		tmpDir := t.TempDir()
-		originalHome := os.Getenv("HOME")
-		os.Setenv("HOME", tmpDir)
-		defer os.Setenv("HOME", originalHome)
-
-		originalStdout := os.Stdout
-		r, w, _ := os.Pipe()
-		os.Stdout = w
+	originalYes := clearYesFlag
+	defer func() { clearYesFlag = originalYes }()
+	clearYesFlag = true
 
-		cmd := &cobra.Command{}
-		clearAllTips(cmd, []string{})
+	t.Run("no tips stored", func(t *testing.T) {
+		useMemoryStore(t)
 
-		w.Close()
-		os.Stdout = originalStdout
-
-		var buf bytes.Buffer
-		_, err := buf.ReadFrom(r)
-		if err != nil {
-			t.Fatalf("Failed to read from pipe: %v", err)
-		}
-		output := buf.String()
+		output := captureStdout(t, func() {
+			clearAllTips(&cobra.Command{}, []string{})
+		})
 
-		if !strings.Contains(output, "No tips file found") {
-			t.Errorf("Expected 'No tips file found' message, got '%s'", output)
+		if !strings.Contains(output, "No tips found") {
+			t.Errorf("Expected 'No tips found' message, got '%s'", output)
 		}
 	})
 
-	t.Run("file exists and is deleted", func(t *testing.T) {
-		// Create temporary directory and file
-		tmpDir := t.TempDir()
-		filePath := filepath.Join(tmpDir, ".tips.json")
-
-		if err := os.WriteFile(filePath, []byte(`{"tips":[]}`), 0644); err != nil {
-			t.Fatalf("Failed to create test file: %v", err)
-		}
-
-		originalHome := os.Getenv("HOME")
-		os.Setenv("HOME", tmpDir)
-		defer os.Setenv("HOME", originalHome)
-
-		originalStdout := os.Stdout
-		r, w, _ := os.Pipe()
-		os.Stdout = w
+	t.Run("tips exist and are deleted", func(t *testing.T) {
+		store := useMemoryStore(t,
+			Tip{ID: "1", Topic: "git", Content: "tip 1", CreatedAt: time.Now()},
+			Tip{ID: "2", Topic: "vim", Content: "tip 2", CreatedAt: time.Now()},
+		)
 
-		cmd := &cobra.Command{}
-		clearAllTips(cmd, []string{})
+		output := captureStdout(t, func() {
+			clearAllTips(&cobra.Command{}, []string{})
+		})
 
-		w.Close()
-		os.Stdout = originalStdout
+		if !strings.Contains(output, "Successfully deleted 2 tips") {
+			t.Errorf("Expected success message, got '%s'", output)
+		}
 
-		var buf bytes.Buffer
-		_, err := buf.ReadFrom(r)
+		tipsData, err := store.Load()
 		if err != nil {
-			t.Fatalf("Failed to read from pipe: %v", err)
+			t.Fatalf("Failed to load store: %v", err)
 		}
-		output := buf.String()
+		if len(tipsData.Tips) != 0 {
+			t.Errorf("Expected store to be empty, got %d tips", len(tipsData.Tips))
+		}
+	})
 
-		if !strings.Contains(output, "Successfully deleted tips file") {
-			t.Errorf("Expected success message, got '%s'", output)
+	t.Run("json file is deleted", func(t *testing.T) {
+		filePath := filepath.Join(t.TempDir(), ".tips.json")
+		if err := os.WriteFile(filePath, []byte(`{"tips":[{"id":"1","topic":"git","content":"tip"}]}`), 0644); err != nil {
+			t.Fatalf("Failed to create test file: %v", err)
 		}
+		useStore(t, newJSONFileStore(filePath))
+
+		captureStdout(t, func() {
+			clearAllTips(&cobra.Command{}, []string{})
+		})
 
 		if _, err := os.Stat(filePath); !os.IsNotExist(err) {
 			t.Error("File should have been deleted")
@@ -220,6 +211,28 @@ func TestClearAllTips(t *testing.T) {
 	})
 }
 
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	originalStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	os.Stdout = w
+
+	fn()
+
+	w.Close()
+	os.Stdout = originalStdout
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r); err != nil {
+		t.Fatalf("Failed to read from pipe: %v", err)
+	}
+	return buf.String()
+}
+
 func TestShowCommandValidation(t *testing.T) {
 	tests := []struct {
 		name        string
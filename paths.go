@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func getTipsFilePath() (string, error) {
+	path, _, err := resolveTipsFilePath()
+	return path, err
+}
+
+func getTipsDBPath() (string, error) {
+	path, explicit, err := resolveTipsFilePath()
+	if err != nil {
+		return "", err
+	}
+	if explicit {
+		return strings.TrimSuffix(path, filepath.Ext(path)) + ".db", nil
+	}
+	return defaultDataPath("tips.db", ".tips.db", "-wal", "-shm")
+}
+
+// getStorePath returns the file backing the configured store. Sidecar files
+// such as the undo journal are named after it.
+func getStorePath() (string, error) {
+	kind, err := storeKind()
+	if err != nil {
+		return "", err
+	}
+	if kind == "sqlite" {
+		return getTipsDBPath()
+	}
+	return getTipsFilePath()
+}
+
+// resolveTipsFilePath checks --file, TIPS_FILE, the active profile and the
+// config file in that order before falling back to the XDG data directory.
+func resolveTipsFilePath() (string, bool, error) {
+	if fileFlag != "" {
+		path, err := expandPath(fileFlag)
+		return path, true, err
+	}
+
+	if path := os.Getenv("TIPS_FILE"); path != "" {
+		path, err := expandPath(path)
+		return path, true, err
+	}
+
+	if profileFile != "" {
+		return profileFile, true, nil
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return "", false, err
+	}
+	if config.File != "" {
+		path, err := expandPath(config.File)
+		return path, true, err
+	}
+
+	path, err := defaultDataPath("tips.json", ".tips.json", ".bak")
+	return path, false, err
+}
+
+func getDataDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dataHome = filepath.Join(homeDir, ".local", "share")
+	}
+	return filepath.Join(dataHome, "tips"), nil
+}
+
+func defaultDataPath(name, legacyName string, sidecarSuffixes ...string) (string, error) {
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return migrateLegacyFile(filepath.Join(homeDir, legacyName), filepath.Join(dataDir, name), sidecarSuffixes...), nil
+}
+
+// migrateLegacyFile moves a file from its pre-XDG location in the home
+// directory. If the move fails the legacy path keeps being used, so a
+// read-only home directory never loses tips.
+func migrateLegacyFile(legacyPath, path string, sidecarSuffixes ...string) string {
+	if _, err := os.Stat(path); err == nil {
+		return path
+	}
+	if _, err := os.Stat(legacyPath); err != nil {
+		return path
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return legacyPath
+	}
+	if err := os.Rename(legacyPath, path); err != nil {
+		return legacyPath
+	}
+
+	for _, suffix := range sidecarSuffixes {
+		os.Rename(legacyPath+suffix, path+suffix)
+	}
+
+	fmt.Fprintf(os.Stderr, "Moved %s to %s\n", legacyPath, path)
+	return path
+}
+
+func expandPath(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(homeDir, path[1:])
+	}
+	return filepath.Abs(path)
+}
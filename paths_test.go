@@ -0,0 +1,171 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func setTestHome(t testing.TB, homeDir string) {
+	t.Helper()
+
+	t.Setenv("HOME", homeDir)
+	t.Setenv("USERPROFILE", homeDir)
+	for _, key := range []string{"XDG_DATA_HOME", "XDG_CONFIG_HOME", "TIPS_FILE", "TIPS_CONFIG", "TIPS_STORE", "TIPS_PROFILE", "TIPS_MODEL", "TIPS_STRATEGY", "TIPS_SALT", "TIPS_PASSPHRASE", "TIPS_KEYFILE"} {
+		t.Setenv(key, "")
+	}
+
+	originalFileFlag, originalStoreFlag, originalProfileFlag, originalModelFlag, originalStrategyFlag := fileFlag, storeFlag, profileFlag, modelFlag, strategyFlag
+	originalProfileFile, originalProfileStore, originalProfileModel := profileFile, profileStore, profileModel
+	fileFlag, storeFlag, profileFlag, modelFlag, strategyFlag = "", "", "", "", ""
+	profileFile, profileStore, profileModel = "", "", ""
+	t.Cleanup(func() {
+		fileFlag, storeFlag, profileFlag, modelFlag, strategyFlag = originalFileFlag, originalStoreFlag, originalProfileFlag, originalModelFlag, originalStrategyFlag
+		profileFile, profileStore, profileModel = originalProfileFile, originalProfileStore, originalProfileModel
+	})
+}
+
+func TestGetTipsFilePath(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	path, err := getTipsFilePath()
+	if err != nil {
+		t.Fatalf("getTipsFilePath failed: %v", err)
+	}
+
+	if expected := filepath.Join(homeDir, ".local", "share", "tips", "tips.json"); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+
+	if !filepath.IsAbs(path) {
+		t.Errorf("Expected absolute path, got %s", path)
+	}
+}
+
+func TestGetTipsFilePath_Precedence(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	t.Setenv("XDG_DATA_HOME", filepath.Join(homeDir, "data"))
+	assertTipsFilePath(t, filepath.Join(homeDir, "data", "tips", "tips.json"))
+
+	writeTestConfig(t, `file = "~/config-tips.json"`)
+	assertTipsFilePath(t, filepath.Join(homeDir, "config-tips.json"))
+
+	t.Setenv("TIPS_FILE", filepath.Join(homeDir, "env-tips.json"))
+	assertTipsFilePath(t, filepath.Join(homeDir, "env-tips.json"))
+
+	fileFlag = filepath.Join(homeDir, "flag-tips.json")
+	assertTipsFilePath(t, filepath.Join(homeDir, "flag-tips.json"))
+}
+
+func assertTipsFilePath(t *testing.T, expected string) {
+	t.Helper()
+
+	path, err := getTipsFilePath()
+	if err != nil {
+		t.Fatalf("getTipsFilePath failed: %v", err)
+	}
+	if path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+}
+
+func TestGetTipsDBPath(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	path, err := getTipsDBPath()
+	if err != nil {
+		t.Fatalf("getTipsDBPath failed: %v", err)
+	}
+	if expected := filepath.Join(homeDir, ".local", "share", "tips", "tips.db"); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+
+	t.Setenv("TIPS_FILE", filepath.Join(homeDir, "work.json"))
+	path, err = getTipsDBPath()
+	if err != nil {
+		t.Fatalf("getTipsDBPath failed: %v", err)
+	}
+	if expected := filepath.Join(homeDir, "work.db"); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+}
+
+func TestLegacyTipsFileMigration(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	legacyPath := filepath.Join(homeDir, ".tips.json")
+	if err := os.WriteFile(legacyPath, []byte(`{"tips": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write legacy file: %v", err)
+	}
+	if err := os.WriteFile(legacyPath+".bak", []byte(`{"tips": []}`), 0644); err != nil {
+		t.Fatalf("Failed to write legacy backup: %v", err)
+	}
+
+	path, err := getTipsFilePath()
+	if err != nil {
+		t.Fatalf("getTipsFilePath failed: %v", err)
+	}
+
+	if !strings.HasPrefix(path, filepath.Join(homeDir, ".local", "share")) {
+		t.Errorf("Expected XDG path, got %s", path)
+	}
+
+	for _, suffix := range []string{"", ".bak"} {
+		if _, err := os.Stat(path + suffix); err != nil {
+			t.Errorf("Expected %s to be migrated: %v", path+suffix, err)
+		}
+		if _, err := os.Stat(legacyPath + suffix); !os.IsNotExist(err) {
+			t.Errorf("Expected %s to be moved", legacyPath+suffix)
+		}
+	}
+}
+
+func TestLegacyTipsFileMigration_ExistingFileWins(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	legacyPath := filepath.Join(homeDir, ".tips.json")
+	newPath := filepath.Join(homeDir, ".local", "share", "tips", "tips.json")
+
+	if err := os.MkdirAll(filepath.Dir(newPath), 0755); err != nil {
+		t.Fatalf("Failed to create data directory: %v", err)
+	}
+	for _, path := range []string{legacyPath, newPath} {
+		if err := os.WriteFile(path, []byte(`{"tips": []}`), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	assertTipsFilePath(t, newPath)
+
+	if _, err := os.Stat(legacyPath); err != nil {
+		t.Error("Legacy file should be left alone when the new file exists")
+	}
+}
+
+func TestExpandPath(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+
+	path, err := expandPath("~/tips.json")
+	if err != nil {
+		t.Fatalf("expandPath failed: %v", err)
+	}
+	if expected := filepath.Join(homeDir, "tips.json"); path != expected {
+		t.Errorf("Expected %s, got %s", expected, path)
+	}
+
+	path, err = expandPath("relative.json")
+	if err != nil {
+		t.Fatalf("expandPath failed: %v", err)
+	}
+	if !filepath.IsAbs(path) {
+		t.Errorf("Expected absolute path, got %s", path)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+const defaultProfileName = "default"
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// The active profile's tips file, store and model, set by applyConfig. They
+// are used when neither a flag nor an environment variable is set.
+var (
+	profileFile  string
+	profileStore string
+	profileModel string
+)
+
+type Profile struct {
+	File    string   `toml:"file,omitempty"`
+	Store   string   `toml:"store,omitempty"`
+	Topics  []string `toml:"topics,omitempty"`
+	Model   string   `toml:"model,omitempty"`
+	Refresh int      `toml:"refresh,omitzero"`
+	Count   int      `toml:"count,omitzero"`
+}
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named tip collections",
+	Long: `Manage profiles: named tip collections with their own store, default topics,
+model and refresh interval.
+
+Select a profile for one command with --profile or TIPS_PROFILE, or for every
+command with 'tips profile use <name>'. The "default" profile is the collection
+used when no profile is selected.`,
+	// Profile commands must work even when the active profile is missing.
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	Run:   listProfiles,
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a profile",
+	Long: `Create a profile from the --file, --store, --topic, --model, --refresh and
+--count flags. Tips are stored in $XDG_DATA_HOME/tips/profiles/<name>.json
+unless --file is given.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := &Profile{}
+		flags := cmd.Flags()
+		if flags.Changed("file") {
+			path, err := expandPath(fileFlag)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error resolving tips file path: %v\n", err)
+				os.Exit(1)
+			}
+			profile.File = path
+		}
+		if flags.Changed("store") {
+			profile.Store = storeFlag
+		}
+		if flags.Changed("topic") {
+			profile.Topics = topicFlag
+		}
+		if flags.Changed("model") {
+			profile.Model = modelFlag
+		}
+		if flags.Changed("refresh") {
+			profile.Refresh = refreshFlag
+		}
+		if flags.Changed("count") {
+			profile.Count = countFlag
+		}
+
+		if err := createProfile(args[0], profile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating profile: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Created profile %s\n", args[0])
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a profile",
+	Long:  `Remove a profile from the config file. Its tips file is left on disk.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := deleteProfile(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting profile: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Deleted profile %s\n", args[0])
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Select the profile used by default",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := useProfile(args[0]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error selecting profile: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Now using profile %s\n", args[0])
+	},
+}
+
+func activeProfileName(config *Config) string {
+	if profileFlag != "" {
+		return profileFlag
+	}
+	if name := os.Getenv("TIPS_PROFILE"); name != "" {
+		return name
+	}
+	if config.CurrentProfile != "" {
+		return config.CurrentProfile
+	}
+	return defaultProfileName
+}
+
+// activeProfile returns a nil profile when the default collection is in use.
+func activeProfile(config *Config) (string, *Profile, error) {
+	name := activeProfileName(config)
+	if name == defaultProfileName {
+		return name, nil, nil
+	}
+
+	profile, ok := config.Profiles[name]
+	if !ok {
+		return "", nil, fmt.Errorf("profile %q does not exist. Create it with: tips profile create %s", name, name)
+	}
+	return name, profile, nil
+}
+
+func getProfileFilePath(name string, profile *Profile) (string, error) {
+	if profile.File != "" {
+		return expandPath(profile.File)
+	}
+
+	dataDir, err := getDataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataDir, "profiles", name+".json"), nil
+}
+
+func createProfile(name string, profile *Profile) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q. Use letters, digits, '-' and '_'", name)
+	}
+	if name == defaultProfileName {
+		return fmt.Errorf("%q is reserved for the collection used without a profile", name)
+	}
+	if profile.Store != "" {
+		if err := validateStoreKind(profile.Store); err != nil {
+			return err
+		}
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Profiles[name]; ok {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+
+	if config.Profiles == nil {
+		config.Profiles = make(map[string]*Profile)
+	}
+	config.Profiles[name] = profile
+	return saveConfig(config)
+}
+
+func deleteProfile(name string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := config.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q does not exist", name)
+	}
+
+	delete(config.Profiles, name)
+	if config.CurrentProfile == name {
+		config.CurrentProfile = ""
+	}
+	return saveConfig(config)
+}
+
+func useProfile(name string) error {
+	config, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	if name == defaultProfileName {
+		config.CurrentProfile = ""
+	} else if _, ok := config.Profiles[name]; !ok {
+		return fmt.Errorf("profile %q does not exist", name)
+	} else {
+		config.CurrentProfile = name
+	}
+	return saveConfig(config)
+}
+
+func listProfiles(cmd *cobra.Command, args []string) {
+	config, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	names := []string{defaultProfileName}
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names[1:])
+
+	active := activeProfileName(config)
+	for _, name := range names {
+		marker := " "
+		if name == active {
+			marker = "*"
+		}
+
+		path := "default tips file"
+		if profile := config.Profiles[name]; profile != nil {
+			if path, err = getProfileFilePath(name, profile); err != nil {
+				path = "unknown tips file"
+			}
+		}
+		fmt.Printf("%s %s (%s)\n", marker, name, path)
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+func useProfileFlags(t *testing.T) {
+	t.Helper()
+
+	originalTopics, originalRefresh, originalCount, originalRules := topicFlag, refreshFlag, countFlag, activeTopicRules
+	originalFile, originalStore, originalModel := profileFile, profileStore, profileModel
+	topicFlag, refreshFlag, countFlag = []string{}, 60, 20
+	t.Cleanup(func() {
+		topicFlag, refreshFlag, countFlag, activeTopicRules = originalTopics, originalRefresh, originalCount, originalRules
+		profileFile, profileStore, profileModel = originalFile, originalStore, originalModel
+	})
+}
+
+func testFlagSet(t *testing.T, args ...string) *pflag.FlagSet {
+	t.Helper()
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringSliceVarP(&topicFlag, "topic", "t", topicFlag, "")
+	flags.IntVarP(&refreshFlag, "refresh", "r", refreshFlag, "")
+	flags.IntVarP(&countFlag, "count", "c", countFlag, "")
+	flags.StringVarP(&fileFlag, "file", "f", fileFlag, "")
+	flags.StringVar(&storeFlag, "store", storeFlag, "")
+	flags.StringVar(&modelFlag, "model", modelFlag, "")
+	if err := flags.Parse(args); err != nil {
+		t.Fatalf("Failed to parse flags: %v", err)
+	}
+	return flags
+}
+
+func TestActiveProfileName(t *testing.T) {
+	setTestHome(t, t.TempDir())
+
+	config := &Config{}
+	if name := activeProfileName(config); name != defaultProfileName {
+		t.Errorf("Expected %s, got %s", defaultProfileName, name)
+	}
+
+	config.CurrentProfile = "config"
+	if name := activeProfileName(config); name != "config" {
+		t.Errorf("Expected config, got %s", name)
+	}
+
+	t.Setenv("TIPS_PROFILE", "env")
+	if name := activeProfileName(config); name != "env" {
+		t.Errorf("Expected env, got %s", name)
+	}
+
+	profileFlag = "flag"
+	if name := activeProfileName(config); name != "flag" {
+		t.Errorf("Expected flag, got %s", name)
+	}
+}
+
+func TestApplyConfig_Profile(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+	useProfileFlags(t)
+
+	writeTestConfig(t, `
+current_profile = "work"
+
+[profiles.work]
+topics = ["kubernetes", "go"]
+model = "anthropic/claude-3"
+refresh = 15
+count = 5
+store = "sqlite"
+`)
+
+	if err := applyConfig(testFlagSet(t, "--count", "7")); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(topicFlag, []string{"kubernetes", "go"}) {
+		t.Errorf("Expected profile topics, got %v", topicFlag)
+	}
+	if model, _ := getModel(); model != "anthropic/claude-3" {
+		t.Errorf("Expected profile model, got %s", model)
+	}
+	if refreshFlag != 15 {
+		t.Errorf("Expected refresh 15, got %d", refreshFlag)
+	}
+	if countFlag != 7 {
+		t.Errorf("Expected count flag to take precedence, got %d", countFlag)
+	}
+	if kind, _ := storeKind(); kind != "sqlite" || storeFlag != "" {
+		t.Errorf("Expected sqlite store without setting the flag, got %s and %q", kind, storeFlag)
+	}
+	if path, _ := getTipsFilePath(); path != filepath.Join(homeDir, ".local", "share", "tips", "profiles", "work.json") || fileFlag != "" {
+		t.Errorf("Expected the profile file without setting the flag, got %s and %q", path, fileFlag)
+	}
+
+	dbPath, err := getTipsDBPath()
+	if err != nil {
+		t.Fatalf("getTipsDBPath failed: %v", err)
+	}
+	if expected := filepath.Join(homeDir, ".local", "share", "tips", "profiles", "work.db"); dbPath != expected {
+		t.Errorf("Expected profile database %s, got %s", expected, dbPath)
+	}
+}
+
+func TestApplyConfig_ProfileBelowEnvironment(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+	useProfileFlags(t)
+
+	writeTestConfig(t, `
+current_profile = "work"
+
+[profiles.work]
+model = "anthropic/claude-3"
+store = "sqlite"
+`)
+	envFile := filepath.Join(homeDir, "env-tips.json")
+	t.Setenv("TIPS_FILE", envFile)
+	t.Setenv("TIPS_STORE", "json")
+	t.Setenv("TIPS_MODEL", "openai/gpt-4o-mini")
+
+	if err := applyConfig(testFlagSet(t)); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+	if path, _ := getTipsFilePath(); path != envFile {
+		t.Errorf("Expected TIPS_FILE to take precedence over the profile, got %s", path)
+	}
+	if kind, _ := storeKind(); kind != "json" {
+		t.Errorf("Expected TIPS_STORE to take precedence over the profile, got %s", kind)
+	}
+	if model, _ := getModel(); model != "openai/gpt-4o-mini" {
+		t.Errorf("Expected TIPS_MODEL to take precedence over the profile, got %s", model)
+	}
+
+	if err := applyConfig(testFlagSet(t, "--model", "google/gemini-pro")); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+	if model, _ := getModel(); model != "google/gemini-pro" {
+		t.Errorf("Expected --model to take precedence over everything, got %s", model)
+	}
+}
+
+func TestApplyConfig_DefaultProfile(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	useProfileFlags(t)
+
+	if err := applyConfig(testFlagSet(t)); err != nil {
+		t.Fatalf("applyConfig failed: %v", err)
+	}
+	if fileFlag != "" || storeFlag != "" || modelFlag != "" || len(topicFlag) != 0 {
+		t.Errorf("Default profile should leave flags untouched")
+	}
+	if profileFile != "" || profileStore != "" || profileModel != "" {
+		t.Errorf("Default profile should have no file, store or model")
+	}
+}
+
+func TestApplyConfig_MissingProfile(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	useProfileFlags(t)
+
+	profileFlag = "missing"
+	err := applyConfig(testFlagSet(t))
+	if err == nil || !strings.Contains(err.Error(), `profile "missing" does not exist`) {
+		t.Errorf("Expected missing profile error, got %v", err)
+	}
+}
+
+func TestProfileLifecycle(t *testing.T) {
+	setTestHome(t, t.TempDir())
+
+	if err := createProfile("work", &Profile{Topics: []string{"go"}}); err != nil {
+		t.Fatalf("createProfile failed: %v", err)
+	}
+	if err := createProfile("work", &Profile{}); err == nil {
+		t.Error("Expected error creating duplicate profile")
+	}
+
+	for _, name := range []string{"", "default", "bad name", "../escape"} {
+		if err := createProfile(name, &Profile{}); err == nil {
+			t.Errorf("Expected error creating profile %q", name)
+		}
+	}
+	if err := createProfile("typo", &Profile{Store: "sqlite3"}); err == nil || !strings.Contains(err.Error(), "unsupported store") {
+		t.Errorf("Expected an unsupported store error, got %v", err)
+	}
+
+	if err := useProfile("missing"); err == nil {
+		t.Error("Expected error using missing profile")
+	}
+	if err := useProfile("work"); err != nil {
+		t.Fatalf("useProfile failed: %v", err)
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	if config.CurrentProfile != "work" {
+		t.Errorf("Expected current profile work, got %q", config.CurrentProfile)
+	}
+	if profile := config.Profiles["work"]; profile == nil || !reflect.DeepEqual(profile.Topics, []string{"go"}) {
+		t.Errorf("Expected work profile with topics to round-trip, got %+v", profile)
+	}
+
+	if err := deleteProfile("work"); err != nil {
+		t.Fatalf("deleteProfile failed: %v", err)
+	}
+	if err := deleteProfile("work"); err == nil {
+		t.Error("Expected error deleting missing profile")
+	}
+
+	config, err = loadConfig()
+	if err != nil {
+		t.Fatalf("loadConfig failed: %v", err)
+	}
+	if config.CurrentProfile != "" || len(config.Profiles) != 0 {
+		t.Errorf("Expected deleted profile to be deselected, got %+v", config)
+	}
+}
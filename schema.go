@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+const currentSchemaVersion = 6
+
+// Files written before versioning have no schema_version and count as 0.
+type schemaMigration struct {
+	version     int
+	description string
+	migrate     func(doc map[string]any) error
+}
+
+var schemaMigrations = []schemaMigration{
+	{
+		version:     1,
+		description: "add schema_version",
+		migrate:     func(doc map[string]any) error { return nil },
+	},
+	{
+		version:     2,
+		description: "add known_at to tips",
+		migrate:     func(doc map[string]any) error { return nil },
+	},
+	{
+		version:     3,
+		description: "add spaced repetition fields to tips",
+		migrate:     func(doc map[string]any) error { return nil },
+	},
+	{
+		version:     4,
+		description: "add rating to tips",
+		migrate:     func(doc map[string]any) error { return nil },
+	},
+	{
+		version:     5,
+		description: "add tags to tips",
+		migrate:     func(doc map[string]any) error { return nil },
+	},
+	{
+		version:     6,
+		description: "add source to tips",
+		// Before 'tips add', every tip came from 'tips generate'.
+		migrate: func(doc map[string]any) error {
+			return forEachTipDoc(doc, func(tip map[string]any) error {
+				if _, ok := tip["source"]; !ok {
+					tip["source"] = sourceLLM
+				}
+				return nil
+			})
+		},
+	},
+}
+
+type newerSchemaError struct {
+	version int
+}
+
+func (e *newerSchemaError) Error() string {
+	return fmt.Sprintf("tips data uses schema version %d, but this version of tips only supports up to %d. Please upgrade tips before modifying it", e.version, currentSchemaVersion)
+}
+
+func checkSchemaWritable(version int) error {
+	if version > currentSchemaVersion {
+		return &newerSchemaError{version: version}
+	}
+	return nil
+}
+
+func readSchemaVersion(data []byte) (int, error) {
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil {
+		return 0, err
+	}
+	return header.SchemaVersion, nil
+}
+
+// migrateSchema upgrades a document from version to currentSchemaVersion.
+// Documents from a newer version are returned unchanged.
+func migrateSchema(data []byte, version int) ([]byte, error) {
+	if version >= currentSchemaVersion {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	for _, m := range schemaMigrations {
+		if m.version <= version {
+			continue
+		}
+		if err := m.migrate(doc); err != nil {
+			return nil, fmt.Errorf("failed to migrate tips data to schema version %d (%s): %w", m.version, m.description, err)
+		}
+		doc["schema_version"] = m.version
+	}
+
+	return json.Marshal(doc)
+}
+
+func forEachTipDoc(doc map[string]any, fn func(tip map[string]any) error) error {
+	tips, ok := doc["tips"].([]any)
+	if !ok {
+		return nil
+	}
+	for _, tip := range tips {
+		if tipDoc, ok := tip.(map[string]any); ok {
+			if err := fn(tipDoc); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
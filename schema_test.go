@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemaMigrations_Ordered(t *testing.T) {
+	if len(schemaMigrations) != currentSchemaVersion {
+		t.Fatalf("Expected %d migrations, got %d", currentSchemaVersion, len(schemaMigrations))
+	}
+
+	for i, m := range schemaMigrations {
+		if m.version != i+1 {
+			t.Errorf("Migration %d has version %d, expected %d", i, m.version, i+1)
+		}
+	}
+}
+
+func TestDecodeTipsData_Versions(t *testing.T) {
+	tests := []struct {
+		name            string
+		data            string
+		expectedVersion int
+		expectedTips    int
+	}{
+		{
+			name:            "unversioned file",
+			data:            `{"tips": [{"id": "1", "topic": "git", "content": "test", "created_at": "2024-01-01T00:00:00Z"}]}`,
+			expectedVersion: currentSchemaVersion,
+			expectedTips:    1,
+		},
+		{
+			name:            "current version",
+			data:            `{"schema_version": 1, "tips": []}`,
+			expectedVersion: currentSchemaVersion,
+			expectedTips:    0,
+		},
+		{
+			name:            "newer version",
+			data:            `{"schema_version": 99, "tips": [{"id": "1", "topic": "git", "content": "test", "created_at": "2024-01-01T00:00:00Z", "future": true}]}`,
+			expectedVersion: 99,
+			expectedTips:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tipsData, err := decodeTipsData([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("decodeTipsData failed: %v", err)
+			}
+
+			if tipsData.SchemaVersion != tt.expectedVersion {
+				t.Errorf("Expected schema version %d, got %d", tt.expectedVersion, tipsData.SchemaVersion)
+			}
+
+			if len(tipsData.Tips) != tt.expectedTips {
+				t.Errorf("Expected %d tips, got %d", tt.expectedTips, len(tipsData.Tips))
+			}
+		})
+	}
+}
+
+func TestJSONFileStore_WritesSchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.json")
+	store := newJSONFileStore(path)
+
+	if err := store.Save(&TipsData{}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read tips file: %v", err)
+	}
+
+	version, err := readSchemaVersion(data)
+	if err != nil {
+		t.Fatalf("Failed to read schema version: %v", err)
+	}
+	if version != currentSchemaVersion {
+		t.Errorf("Expected schema version %d on disk, got %d", currentSchemaVersion, version)
+	}
+}
+
+func TestJSONFileStore_RefusesNewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.json")
+	original := `{"schema_version": 99, "tips": [{"id": "1", "topic": "git", "content": "test", "created_at": "2024-01-01T00:00:00Z"}]}`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatalf("Failed to write tips file: %v", err)
+	}
+
+	store := newJSONFileStore(path)
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(tipsData.Tips) != 1 {
+		t.Fatalf("Expected 1 tip, got %d", len(tipsData.Tips))
+	}
+
+	var schemaErr *newerSchemaError
+	if err := store.Save(tipsData); !errors.As(err, &schemaErr) {
+		t.Errorf("Expected Save to refuse newer schema, got %v", err)
+	}
+	if err := store.Save(&TipsData{}); !errors.As(err, &schemaErr) {
+		t.Errorf("Expected Save of fresh data to refuse newer schema on disk, got %v", err)
+	}
+	if err := store.Add(Tip{ID: "2", Topic: "git", Content: "other"}); !errors.As(err, &schemaErr) {
+		t.Errorf("Expected Add to refuse newer schema, got %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read tips file: %v", err)
+	}
+	if string(data) != original {
+		t.Errorf("Tips file should be untouched, got %s", data)
+	}
+}
+
+func TestSQLiteStore_SchemaVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.db")
+
+	store, err := newSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open sqlite store: %v", err)
+	}
+
+	tip := Tip{ID: "1", Topic: "git", Content: "test"}
+	if err := store.Add(tip); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	var version int
+	if err := store.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		t.Fatalf("Failed to read user_version: %v", err)
+	}
+	if version != currentSchemaVersion {
+		t.Errorf("Expected user_version %d, got %d", currentSchemaVersion, version)
+	}
+
+	if _, err := store.db.Exec("PRAGMA user_version = 99"); err != nil {
+		t.Fatalf("Failed to set user_version: %v", err)
+	}
+	store.Close()
+
+	store, err = newSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen sqlite store: %v", err)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(tipsData.Tips) != 1 || tipsData.SchemaVersion != 99 {
+		t.Errorf("Expected 1 tip at schema version 99, got %d tips at %d", len(tipsData.Tips), tipsData.SchemaVersion)
+	}
+
+	err = store.Add(Tip{ID: "2", Topic: "git", Content: "other"})
+	if err == nil || !strings.Contains(err.Error(), "schema version 99") {
+		t.Errorf("Expected Add to refuse newer schema, got %v", err)
+	}
+}
+
+func TestSQLiteStore_MigratesOlderRows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.db")
+
+	store, err := newSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open sqlite store: %v", err)
+	}
+	data, _ := json.Marshal(Tip{ID: "1", Topic: "git", Content: "test"})
+	if _, err := store.db.Exec("INSERT INTO tips (id, topic, created_at, data) VALUES ('1', 'git', 0, ?)", string(data)); err != nil {
+		t.Fatalf("Failed to insert tip: %v", err)
+	}
+	if _, err := store.db.Exec("PRAGMA user_version = 0"); err != nil {
+		t.Fatalf("Failed to reset user_version: %v", err)
+	}
+	store.Close()
+
+	store, err = newSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen sqlite store: %v", err)
+	}
+	defer store.Close()
+
+	if store.version != currentSchemaVersion {
+		t.Errorf("Expected store migrated to version %d, got %d", currentSchemaVersion, store.version)
+	}
+
+	tips, err := store.QueryByTopic([]string{"git"})
+	if err != nil {
+		t.Fatalf("QueryByTopic failed: %v", err)
+	}
+	if len(tips) != 1 || tips[0].Content != "test" || tips[0].Source != sourceLLM {
+		t.Errorf("Expected migrated tip to survive, got %+v", tips)
+	}
+}
+
+func TestMigrateSchema_Source(t *testing.T) {
+	tipsData, err := decodeTipsData([]byte(`{"schema_version": 5, "tips": [{"id": "1", "topic": "git", "content": "test"}]}`))
+	if err != nil {
+		t.Fatalf("decodeTipsData failed: %v", err)
+	}
+	if tipsData.Tips[0].Source != sourceLLM {
+		t.Errorf("Expected tips from before 'tips add' to come from the model, got %q", tipsData.Tips[0].Source)
+	}
+
+	tipsData, err = decodeTipsData([]byte(`{"schema_version": 6, "tips": [{"id": "1", "topic": "git", "content": "test"}]}`))
+	if err != nil {
+		t.Fatalf("decodeTipsData failed: %v", err)
+	}
+	if tipsData.Tips[0].Source != "" {
+		t.Errorf("Expected current tips to be left alone, got %q", tipsData.Tips[0].Source)
+	}
+}
@@ -0,0 +1,516 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/gofrs/flock"
+	"github.com/spf13/cobra"
+)
+
+// searchIndexVersion changes whenever tokenize or the index format does, so
+// old indexes are rebuilt rather than silently missing terms.
+const searchIndexVersion = 3
+
+// BM25 parameters, at their usual values.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+var searchLimitFlag int
+
+// An indexedDoc is a tip as it was indexed, with its distinct terms so it
+// can be dropped from Postings without a pass over the whole vocabulary, and
+// a hash of the indexed text to tell whether the tip has changed since.
+type indexedDoc struct {
+	Length int      `json:"length"`
+	Terms  []string `json:"terms"`
+	Hash   uint64   `json:"hash"`
+}
+
+// searchIndex is an inverted index over tip topics, tags and content, kept in
+// a file next to the store. Postings maps each term to the tips containing it
+// and how often. indexedStore updates it as tips are written, so a search
+// only reads it.
+type searchIndex struct {
+	path     string
+	Version  int                       `json:"version"`
+	Docs     map[string]indexedDoc     `json:"docs"`
+	Postings map[string]map[string]int `json:"postings"`
+
+	totalLength int
+}
+
+func searchIndexPath(storePath string) string {
+	return storePath + ".index"
+}
+
+// openSearchIndex loads the index next to the store holding td. It is built
+// from td if it is missing or unusable, or doesn't hold td's tips as they are
+// now, which happens when the tips were written without going through an
+// indexedStore, such as a restore from backup or an edit by hand. Encrypted tips files get an index that is never written,
+// since it would hold their words in plain text.
+func openSearchIndex(td *TipsData) (*searchIndex, error) {
+	storePath, err := getStorePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tips store path: %w", err)
+	}
+
+	kind, err := storeKind()
+	if err != nil {
+		return nil, err
+	}
+	if kind == "json" {
+		if _, encrypted := newJSONFileStore(storePath).diskFormat(); encrypted {
+			return buildSearchIndex("", td), nil
+		}
+	}
+
+	path := searchIndexPath(storePath)
+	idx, err := loadSearchIndex(path)
+	if err != nil {
+		return nil, err
+	}
+	if idx != nil && idx.covers(td) {
+		return idx, nil
+	}
+
+	unlock, err := lockSearchIndex(path)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	idx = buildSearchIndex(path, td)
+	return idx, idx.save()
+}
+
+func newSearchIndex(path string) *searchIndex {
+	return &searchIndex{
+		path:     path,
+		Version:  searchIndexVersion,
+		Docs:     make(map[string]indexedDoc),
+		Postings: make(map[string]map[string]int),
+	}
+}
+
+func buildSearchIndex(path string, td *TipsData) *searchIndex {
+	idx := newSearchIndex(path)
+	for i := range td.Tips {
+		idx.add(&td.Tips[i])
+	}
+	return idx
+}
+
+// covers reports whether the index holds exactly the tips in td, as they are
+// now.
+func (idx *searchIndex) covers(td *TipsData) bool {
+	if len(idx.Docs) != len(td.Tips) {
+		return false
+	}
+	for i := range td.Tips {
+		doc, ok := idx.Docs[td.Tips[i].ID]
+		if !ok || doc.Hash != tipHash(&td.Tips[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadSearchIndex returns nil if the index is missing, unreadable or from
+// another version; it can always be rebuilt from the tips.
+func loadSearchIndex(path string) (*searchIndex, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+
+	idx := newSearchIndex(path)
+	if err := json.Unmarshal(data, idx); err != nil || idx.Version != searchIndexVersion || idx.Docs == nil || idx.Postings == nil {
+		return nil, nil
+	}
+	for _, doc := range idx.Docs {
+		idx.totalLength += doc.Length
+	}
+	return idx, nil
+}
+
+// lockSearchIndex serializes updates to the index at path between
+// processes.
+func lockSearchIndex(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create search index directory: %w", err)
+	}
+
+	fileLock := flock.New(path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("failed to lock search index: %w", err)
+	}
+	return func() { fileLock.Unlock() }, nil
+}
+
+func (idx *searchIndex) save() error {
+	if idx.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return fmt.Errorf("failed to create search index directory: %w", err)
+	}
+	if err := writeFileAtomic(idx.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	return nil
+}
+
+// tokenize lowercases text and splits it into runs of letters and digits.
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func tipTerms(tip *Tip) []string {
+	terms := tokenize(tip.Topic)
+	for _, tag := range tip.Tags {
+		terms = append(terms, tokenize(tag)...)
+	}
+	return append(terms, tokenize(tip.Content)...)
+}
+
+// tipHash hashes the parts of tip that are indexed.
+func tipHash(tip *Tip) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(tip.Topic))
+	for _, tag := range tip.Tags {
+		h.Write([]byte{0})
+		h.Write([]byte(tag))
+	}
+	h.Write([]byte{0, 0})
+	h.Write([]byte(tip.Content))
+	return h.Sum64()
+}
+
+// add indexes tip, replacing any earlier version of it.
+func (idx *searchIndex) add(tip *Tip) {
+	idx.remove(tip.ID)
+
+	terms := tipTerms(tip)
+	counts := make(map[string]int)
+	for _, term := range terms {
+		counts[term]++
+	}
+
+	distinct := make([]string, 0, len(counts))
+	for term, tf := range counts {
+		postings, ok := idx.Postings[term]
+		if !ok {
+			postings = make(map[string]int)
+			idx.Postings[term] = postings
+		}
+		postings[tip.ID] = tf
+		distinct = append(distinct, term)
+	}
+	sort.Strings(distinct)
+
+	idx.Docs[tip.ID] = indexedDoc{Length: len(terms), Terms: distinct, Hash: tipHash(tip)}
+	idx.totalLength += len(terms)
+}
+
+func (idx *searchIndex) remove(id string) {
+	doc, ok := idx.Docs[id]
+	if !ok {
+		return
+	}
+	for _, term := range doc.Terms {
+		postings := idx.Postings[term]
+		delete(postings, id)
+		if len(postings) == 0 {
+			delete(idx.Postings, term)
+		}
+	}
+	idx.totalLength -= doc.Length
+	delete(idx.Docs, id)
+}
+
+type searchResult struct {
+	tip   *Tip
+	score float64
+}
+
+// search ranks the tips matching filter against query with BM25. Tips are
+// looked up in td, so any the index has that td doesn't are skipped.
+func (idx *searchIndex) search(td *TipsData, query string, filter tipFilter) []searchResult {
+	terms := uniqueTerms(query)
+	if len(terms) == 0 || len(idx.Docs) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.Docs))
+	avgLength := float64(idx.totalLength) / n
+
+	scores := make(map[string]float64)
+	for _, term := range terms {
+		postings := idx.Postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		df := float64(len(postings))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+		for id, tf := range postings {
+			length := float64(idx.Docs[id].Length)
+			f := float64(tf)
+			scores[id] += idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*length/avgLength))
+		}
+	}
+
+	matches := filter.matcher()
+	var results []searchResult
+	for i := range td.Tips {
+		tip := &td.Tips[i]
+		if score, ok := scores[tip.ID]; ok && matches(tip) {
+			results = append(results, searchResult{tip: tip, score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].score != results[j].score {
+			return results[i].score > results[j].score
+		}
+		return results[i].tip.CreatedAt.After(results[j].tip.CreatedAt)
+	})
+	return results
+}
+
+func uniqueTerms(text string) []string {
+	var terms []string
+	seen := make(map[string]struct{})
+	for _, term := range tokenize(text) {
+		if _, dup := seen[term]; !dup {
+			seen[term] = struct{}{}
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// highlightTerms renders every word of text that is one of terms with
+// render, leaving everything else, including code spans, as it was.
+func highlightTerms(text string, terms []string, render func(string) string) string {
+	termSet := newIDSet(terms)
+
+	var b strings.Builder
+	start := -1
+	flush := func(end int) {
+		if start < 0 {
+			return
+		}
+		word := text[start:end]
+		if _, ok := termSet[strings.ToLower(word)]; ok {
+			word = render(word)
+		}
+		b.WriteString(word)
+		start = -1
+	}
+
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		flush(i)
+		b.WriteRune(r)
+	}
+	flush(len(text))
+	return b.String()
+}
+
+// searchTips runs query against the index of the store tipsData was loaded
+// from.
+func searchTips(tipsData *TipsData, query string, filter tipFilter) ([]searchResult, error) {
+	idx, err := openSearchIndex(tipsData)
+	if err != nil {
+		return nil, err
+	}
+	return idx.search(tipsData, query, filter), nil
+}
+
+// indexedStore keeps the search index next to a store up to date as tips
+// are added, changed and removed. The index is a cache: if it can't be
+// updated it is deleted, to be rebuilt by the next search, rather than
+// failing a write that has already succeeded.
+type indexedStore struct {
+	TipStore
+	indexPath string
+}
+
+func newIndexedStore(store TipStore, path string) *indexedStore {
+	return &indexedStore{TipStore: store, indexPath: path}
+}
+
+func (s *indexedStore) Save(tipsData *TipsData) error {
+	// Save replaces the tips wholesale unless tipsData was loaded from the
+	// store, in which case only its changes are applied.
+	var added, updated []Tip
+	var removed []string
+	wholesale := tipsData != nil && tipsData.loaded == nil
+	if tipsData != nil && !wholesale {
+		added, updated, removed = tipsData.delta()
+	}
+
+	if err := s.TipStore.Save(tipsData); err != nil {
+		return err
+	}
+	if wholesale {
+		s.dropIndex()
+		return nil
+	}
+	s.updateIndex(func(idx *searchIndex) {
+		for _, tip := range append(added, updated...) {
+			idx.add(&tip)
+		}
+		for _, id := range removed {
+			idx.remove(id)
+		}
+	})
+	return nil
+}
+
+func (s *indexedStore) Add(tips ...Tip) error {
+	if err := s.TipStore.Add(tips...); err != nil {
+		return err
+	}
+	s.updateIndex(func(idx *searchIndex) {
+		for i := range tips {
+			idx.add(&tips[i])
+		}
+	})
+	return nil
+}
+
+func (s *indexedStore) Update(tips ...Tip) error {
+	if err := s.TipStore.Update(tips...); err != nil {
+		return err
+	}
+	s.updateIndex(func(idx *searchIndex) {
+		for i := range tips {
+			if _, ok := idx.Docs[tips[i].ID]; ok {
+				idx.add(&tips[i])
+			}
+		}
+	})
+	return nil
+}
+
+func (s *indexedStore) Remove(ids ...string) (int, error) {
+	n, err := s.TipStore.Remove(ids...)
+	if err != nil {
+		return n, err
+	}
+	s.updateIndex(func(idx *searchIndex) {
+		for _, id := range ids {
+			idx.remove(id)
+		}
+	})
+	return n, nil
+}
+
+func (s *indexedStore) Clear() error {
+	if err := s.TipStore.Clear(); err != nil {
+		return err
+	}
+	s.dropIndex()
+	return nil
+}
+
+// updateIndex applies update to the saved index. An index that doesn't exist
+// yet is left for the next search to build from every tip.
+func (s *indexedStore) updateIndex(update func(idx *searchIndex)) {
+	unlock, err := lockSearchIndex(s.indexPath)
+	if err != nil {
+		s.dropIndex()
+		return
+	}
+	defer unlock()
+
+	idx, err := loadSearchIndex(s.indexPath)
+	if err != nil || idx == nil {
+		s.dropIndex()
+		return
+	}
+	update(idx)
+	if err := idx.save(); err != nil {
+		s.dropIndex()
+	}
+}
+
+func (s *indexedStore) dropIndex() {
+	os.Remove(s.indexPath)
+}
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>...",
+	Short: "Search tips by their words, best matches first",
+	Long: `Search the topics, tags and content of every tip, including those marked as
+known, and list the best matches first with the matching words highlighted.
+
+Results are ranked with BM25, so rarer words and shorter tips count for more.
+Filter with --topic, --tag and --not-tag. The search index is kept next to the
+tips file and updated as tips are added, edited and deleted.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  runSearch,
+}
+
+func runSearch(cmd *cobra.Command, args []string) {
+	if searchLimitFlag <= 0 {
+		fmt.Fprintf(os.Stderr, "Error: Limit must be greater than 0\n")
+		os.Exit(1)
+	}
+
+	tipsData, err := loadTips()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	query := strings.Join(args, " ")
+	results, err := searchTips(tipsData, query, commandFilter(cmd))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error searching tips: %v\n", err)
+		os.Exit(1)
+	}
+	if len(results) == 0 {
+		fmt.Printf("No tips match %q\n", query)
+		return
+	}
+
+	terms := uniqueTerms(query)
+	matchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Bold(true)
+	highlight := func(word string) string { return matchStyle.Render(word) }
+	for _, result := range results[:min(searchLimitFlag, len(results))] {
+		known := ""
+		if result.tip.isKnown() {
+			known = " (known)"
+		}
+		fmt.Printf("%s [%s]%s\n  %s\n", result.tip.ID, result.tip.Topic, known, highlightTerms(result.tip.Content, terms, highlight))
+	}
+}
@@ -0,0 +1,293 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func searchIDs(results []searchResult) string {
+	ids := make([]string, len(results))
+	for i, result := range results {
+		ids[i] = result.tip.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestTokenize(t *testing.T) {
+	expected := []string{"use", "git", "rebase", "i", "head", "3", "für", "ünïcode"}
+	if got := tokenize("Use `git rebase -i HEAD~3` für Ünïcode"); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected %v, got %v", expected, got)
+	}
+}
+
+func TestSearchIndex_search(t *testing.T) {
+	td := &TipsData{Tips: []Tip{
+		{ID: "1", Topic: "git", Content: "git rebase -i lets you reword, squash and reorder commits in an interactive session"},
+		{ID: "2", Topic: "git", Content: "git rebase --interactive HEAD~3"},
+		{ID: "3", Topic: "git", Content: "git stash pop restores stashed changes"},
+		{ID: "4", Topic: "vim", Content: "Use :s with the c flag for an interactive substitution", Tags: []string{"search"}},
+		{ID: "5", Topic: "go/testing", Content: "t.Run runs subtests"},
+	}}
+
+	idx := buildSearchIndex("", td)
+
+	tests := []struct {
+		name     string
+		query    string
+		filter   tipFilter
+		expected string
+	}{
+		{name: "shorter tips rank higher", query: "rebase interactive", expected: "2,1,4"},
+		{name: "rare terms rank higher", query: "interactive substitution", expected: "4,2,1"},
+		{name: "case and punctuation ignored", query: "STASH-pop!", expected: "3"},
+		{name: "topics are indexed", query: "testing", expected: "5"},
+		{name: "tags are indexed", query: "search", expected: "4"},
+		{name: "filtered", query: "interactive", filter: tipFilter{topics: []string{"vim"}}, expected: "4"},
+		{name: "no match", query: "kubernetes", expected: ""},
+		{name: "empty query", query: " -- ", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := searchIDs(idx.search(td, tt.query, tt.filter)); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSearchIndex_addRemove(t *testing.T) {
+	td := &TipsData{Tips: []Tip{
+		{ID: "1", Topic: "git", Content: "git stash pop"},
+		{ID: "2", Topic: "vim", Content: "dd deletes a line"},
+	}}
+	idx := buildSearchIndex("", td)
+
+	td.Tips[0].Content = "git stash apply"
+	idx.add(&td.Tips[0])
+	td.removeTip("2")
+	idx.remove("2")
+	td.Tips = append(td.Tips, Tip{ID: "3", Topic: "go", Content: "go vet finds bugs"})
+	idx.add(&td.Tips[1])
+
+	if _, ok := idx.Postings["pop"]; ok {
+		t.Error("Expected the old content to be dropped")
+	}
+	if _, ok := idx.Docs["2"]; ok {
+		t.Error("Expected the deleted tip to be dropped")
+	}
+	if got := searchIDs(idx.search(td, "apply vet dd", tipFilter{})); got != "1,3" && got != "3,1" {
+		t.Errorf("Expected tips 1 and 3, got %q", got)
+	}
+
+	fresh := buildSearchIndex("", td)
+	if !reflect.DeepEqual(idx.Postings, fresh.Postings) || !reflect.DeepEqual(idx.Docs, fresh.Docs) || idx.totalLength != fresh.totalLength {
+		t.Error("Expected an incrementally updated index to match a fresh one")
+	}
+}
+
+func TestLoadSearchIndex(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.json.index")
+	td := &TipsData{Tips: []Tip{{ID: "1", Topic: "git", Content: "git stash pop"}}}
+
+	if idx, err := loadSearchIndex(path); err != nil || idx != nil {
+		t.Fatalf("Expected no index, got %+v (%v)", idx, err)
+	}
+	idx := buildSearchIndex(path, td)
+	if err := idx.save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	loaded, err := loadSearchIndex(path)
+	if err != nil {
+		t.Fatalf("loadSearchIndex failed: %v", err)
+	}
+	if loaded == nil || !reflect.DeepEqual(loaded.Docs, idx.Docs) || loaded.totalLength != idx.totalLength {
+		t.Errorf("Expected the saved index, got %+v", loaded)
+	}
+
+	for _, contents := range []string{"{not json", `{"version": 0, "docs": {}, "postings": {}}`} {
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatalf("Failed to write index: %v", err)
+		}
+		loaded, err := loadSearchIndex(path)
+		if err != nil {
+			t.Fatalf("loadSearchIndex failed: %v", err)
+		}
+		if loaded != nil {
+			t.Errorf("Expected %q to need a rebuild, got %+v", contents, loaded.Docs)
+		}
+	}
+}
+
+func TestIndexedStore(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	filePath := filepath.Join(t.TempDir(), "tips.json")
+	t.Setenv("TIPS_FILE", filePath)
+	indexPath := searchIndexPath(filePath)
+
+	store, err := openStore()
+	if err != nil {
+		t.Fatalf("openStore failed: %v", err)
+	}
+	defer store.Close()
+
+	search := func(query string) string {
+		t.Helper()
+		tips, err := store.Load()
+		if err != nil {
+			t.Fatalf("Load failed: %v", err)
+		}
+		results, err := searchTips(tips, query, tipFilter{})
+		if err != nil {
+			t.Fatalf("searchTips failed: %v", err)
+		}
+		return searchIDs(results)
+	}
+	indexed := func() map[string]indexedDoc {
+		t.Helper()
+		idx, err := loadSearchIndex(indexPath)
+		if err != nil || idx == nil {
+			t.Fatalf("Expected a saved index, got %v", err)
+		}
+		return idx.Docs
+	}
+
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "git stash pop"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Error("Expected the index to be built by the first search, not a write")
+	}
+	if got := search("stash"); got != "1" {
+		t.Errorf("Expected tip 1, got %q", got)
+	}
+
+	if err := store.Add(Tip{ID: "2", Topic: "vim", Content: "dd deletes a line"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, ok := indexed()["2"]; !ok {
+		t.Error("Expected Add to index the new tip")
+	}
+
+	if err := store.Update(Tip{ID: "1", Topic: "git", Content: "git stash apply"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got := search("pop"); got != "" {
+		t.Errorf("Expected the old content to be unindexed, got %q", got)
+	}
+
+	if _, err := store.Remove("2"); err != nil {
+		t.Fatalf("Remove failed: %v", err)
+	}
+	if _, ok := indexed()["2"]; ok {
+		t.Error("Expected Remove to unindex the tip")
+	}
+
+	if _, err := store.(*journaledStore).Undo(); err != nil {
+		t.Fatalf("Undo failed: %v", err)
+	}
+	if got := search("dd"); got != "2" {
+		t.Errorf("Expected the restored tip to be found, got %q", got)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+	if _, err := os.Stat(indexPath); !os.IsNotExist(err) {
+		t.Error("Expected Clear to remove the index")
+	}
+}
+
+func TestOpenSearchIndex_RebuildsStale(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	filePath := filepath.Join(t.TempDir(), "tips.json")
+	t.Setenv("TIPS_FILE", filePath)
+
+	if err := saveTips(&TipsData{Tips: []Tip{{ID: "1", Topic: "git", Content: "git stash pop"}}}); err != nil {
+		t.Fatalf("saveTips failed: %v", err)
+	}
+	search := func(query string) string {
+		t.Helper()
+		tips, err := loadTips()
+		if err != nil {
+			t.Fatalf("loadTips failed: %v", err)
+		}
+		results, err := searchTips(tips, query, tipFilter{})
+		if err != nil {
+			t.Fatalf("searchTips failed: %v", err)
+		}
+		return searchIDs(results)
+	}
+	if got := search("stash"); got != "1" {
+		t.Fatalf("Expected tip 1, got %q", got)
+	}
+
+	// An edit that bypasses the index but keeps the number of tips.
+	if err := newJSONFileStore(filePath).Update(Tip{ID: "1", Topic: "git", Content: "git rebase -i"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got := search("rebase"); got != "1" {
+		t.Errorf("Expected the edited tip to be found, got %q", got)
+	}
+	if got := search("stash"); got != "" {
+		t.Errorf("Expected the old content to be gone, got %q", got)
+	}
+}
+
+func TestHighlightTerms(t *testing.T) {
+	render := func(word string) string { return "*" + word + "*" }
+	got := highlightTerms("Use `git Rebase -i` to rebase; rebased isn't", []string{"rebase", "i"}, render)
+	if expected := "Use `git *Rebase* -*i*` to *rebase*; rebased isn't"; got != expected {
+		t.Errorf("Expected %q, got %q", expected, got)
+	}
+}
+
+func TestRunSearch(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	originalTopics, originalLimit := topicFlag, searchLimitFlag
+	defer func() { topicFlag, searchLimitFlag = originalTopics, originalLimit }()
+	topicFlag, searchLimitFlag = []string{}, 1
+
+	filePath := filepath.Join(t.TempDir(), "tips.json")
+	t.Setenv("TIPS_FILE", filePath)
+	known := time.Now()
+	if err := saveTips(&TipsData{Tips: []Tip{
+		{ID: "1", Topic: "git", Content: "git rebase -i HEAD~3", KnownAt: &known},
+		{ID: "2", Topic: "git", Content: "git rebase onto another branch and rebase again"},
+	}}); err != nil {
+		t.Fatalf("saveTips failed: %v", err)
+	}
+
+	output := captureStdout(t, func() { runSearch(&cobra.Command{}, []string{"rebase", "HEAD"}) })
+	if !strings.HasPrefix(output, "1 [git] (known)\n") || strings.Contains(output, "another branch") {
+		t.Errorf("Expected only the best match, got '%s'", output)
+	}
+	if _, err := os.Stat(searchIndexPath(filePath)); err != nil {
+		t.Errorf("Expected the index to be saved next to the tips file: %v", err)
+	}
+
+	// A default topic from the config file doesn't hide other matches.
+	topicFlag = []string{"vim"}
+	output = captureStdout(t, func() { runSearch(&cobra.Command{}, []string{"rebase"}) })
+	if !strings.Contains(output, "[git]") {
+		t.Errorf("Expected default topics to be ignored, got '%s'", output)
+	}
+
+	cmd := topicCommand(t, "vim")
+	output = captureStdout(t, func() { runSearch(cmd, []string{"rebase"}) })
+	if !strings.Contains(output, `No tips match "rebase"`) {
+		t.Errorf("Expected -t to narrow the search, got '%s'", output)
+	}
+
+	output = captureStdout(t, func() { runSearch(&cobra.Command{}, []string{"kubernetes"}) })
+	if !strings.Contains(output, `No tips match "kubernetes"`) {
+		t.Errorf("Expected no matches, got '%s'", output)
+	}
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// A shuffleBag deals every matching tip once, in random order, before
+// starting a new round. Order holds the whole round and Cursor the number of
+// tips already dealt.
+type shuffleBag struct {
+	Order  []string `json:"order"`
+	Cursor int      `json:"cursor"`
+}
+
+// shuffleState keeps one bag per topic filter and persists them next to the
+// store, so the rotation survives restarts.
+type shuffleState struct {
+	path string
+	rng  tipRand
+	Bags map[string]*shuffleBag `json:"bags"`
+}
+
+func shuffleStatePath(storePath string) string {
+	return storePath + ".shuffle"
+}
+
+func openShuffleState() (*shuffleState, error) {
+	storePath, err := getStorePath()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tips store path: %w", err)
+	}
+	return loadShuffleState(shuffleStatePath(storePath))
+}
+
+// loadShuffleState starts afresh if the state file is missing or unreadable;
+// losing it only resets the rotation.
+func loadShuffleState(path string) (*shuffleState, error) {
+	state := &shuffleState{path: path, Bags: make(map[string]*shuffleBag)}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shuffle state: %w", err)
+	}
+
+	if err := json.Unmarshal(data, state); err != nil || state.Bags == nil {
+		state.Bags = make(map[string]*shuffleBag)
+	}
+	return state, nil
+}
+
+func (s *shuffleState) save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal shuffle state: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create shuffle state directory: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write shuffle state: %w", err)
+	}
+	return nil
+}
+
+// shuffleKey names the bag for a filter. Topic-only filters are keyed by their
+// sorted topics; tag filters are appended after a "|".
+func shuffleKey(filter tipFilter) string {
+	key := sortedJoin(filter.topics)
+	if len(filter.tags) > 0 || len(filter.notTags) > 0 {
+		key += "|" + sortedJoin(normalizeTags(filter.tags)) + "|" + sortedJoin(normalizeTags(filter.notTags))
+	}
+	return key
+}
+
+func sortedJoin(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// pick deals the next tip from the bag for filter and saves the state. Tips
+// removed or marked as known since the bag was dealt are skipped, and new
+// tips are shuffled into the rest of the current round.
+func (s *shuffleState) pick(td *TipsData, filter tipFilter) (*Tip, error) {
+	key := shuffleKey(filter)
+	bag := s.Bags[key]
+	if bag == nil {
+		bag = &shuffleBag{}
+		s.Bags[key] = bag
+	}
+	bag.Cursor = min(max(bag.Cursor, 0), len(bag.Order))
+
+	selectable := newTipFilter(filter)
+	matching := make(map[string]*Tip)
+	for i := range td.Tips {
+		if selectable(&td.Tips[i]) {
+			matching[td.Tips[i].ID] = &td.Tips[i]
+		}
+	}
+	if len(matching) == 0 {
+		return nil, nil
+	}
+
+	inBag := newIDSet(bag.Order)
+	var remaining []string
+	for _, id := range bag.Order[bag.Cursor:] {
+		if _, ok := matching[id]; ok {
+			remaining = append(remaining, id)
+		}
+	}
+	for i := range td.Tips {
+		id := td.Tips[i].ID
+		if _, ok := inBag[id]; ok {
+			continue
+		}
+		if _, ok := matching[id]; ok {
+			at := s.rng.Intn(len(remaining) + 1)
+			remaining = append(remaining[:at], append([]string{id}, remaining[at:]...)...)
+		}
+	}
+
+	if len(remaining) == 0 {
+		var last string
+		if bag.Cursor > 0 {
+			last = bag.Order[bag.Cursor-1]
+		}
+		remaining = newShuffledRound(s.rng, matching, last)
+		bag.Order = bag.Order[:0]
+		bag.Cursor = 0
+	}
+
+	bag.Order = append(bag.Order[:bag.Cursor], remaining...)
+	tip := matching[bag.Order[bag.Cursor]]
+	bag.Cursor++
+
+	return tip, s.save()
+}
+
+// newShuffledRound shuffles the matching tips, keeping last from being dealt
+// twice in a row across rounds.
+func newShuffledRound(rng tipRand, matching map[string]*Tip, last string) []string {
+	ids := make([]string, 0, len(matching))
+	for id := range matching {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	rng.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+
+	if len(ids) > 1 && ids[0] == last {
+		swap := 1 + rng.Intn(len(ids)-1)
+		ids[0], ids[swap] = ids[swap], ids[0]
+	}
+	return ids
+}
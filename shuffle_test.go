@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func dealTips(t *testing.T, state *shuffleState, td *TipsData, filter tipFilter, n int) []string {
+	t.Helper()
+
+	var ids []string
+	for i := 0; i < n; i++ {
+		tip, err := state.pick(td, filter)
+		if err != nil {
+			t.Fatalf("pick failed: %v", err)
+		}
+		if tip == nil {
+			t.Fatal("Expected a tip, got nil")
+		}
+		ids = append(ids, tip.ID)
+	}
+	return ids
+}
+
+func TestShuffleState_DealsEveryTipOncePerRound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.json.shuffle")
+	td := &TipsData{Tips: []Tip{
+		{ID: "1", Topic: "git"},
+		{ID: "2", Topic: "git"},
+		{ID: "3", Topic: "git"},
+		{ID: "4", Topic: "vim"},
+	}}
+
+	state, err := loadShuffleState(path)
+	if err != nil {
+		t.Fatalf("loadShuffleState failed: %v", err)
+	}
+
+	var previous string
+	for round := 0; round < 5; round++ {
+		dealt := dealTips(t, state, td, tipFilter{topics: []string{"git"}}, 3)
+		if seen := newIDSet(dealt); len(seen) != 3 {
+			t.Fatalf("Round %d repeated a tip: %v", round, dealt)
+		}
+		if dealt[0] == previous {
+			t.Fatalf("Round %d started with the tip that ended the last one: %s", round, previous)
+		}
+		previous = dealt[2]
+	}
+}
+
+func TestShuffleState_PersistsRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.json.shuffle")
+	td := &TipsData{Tips: []Tip{{ID: "1"}, {ID: "2"}, {ID: "3"}, {ID: "4"}}}
+
+	state, err := loadShuffleState(path)
+	if err != nil {
+		t.Fatalf("loadShuffleState failed: %v", err)
+	}
+	dealt := dealTips(t, state, td, tipFilter{}, 2)
+
+	state, err = loadShuffleState(path)
+	if err != nil {
+		t.Fatalf("loadShuffleState failed: %v", err)
+	}
+	dealt = append(dealt, dealTips(t, state, td, tipFilter{}, 2)...)
+
+	if seen := newIDSet(dealt); len(seen) != 4 {
+		t.Errorf("Expected the rotation to continue after reloading, got %v", dealt)
+	}
+}
+
+func TestShuffleState_FollowsChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.json.shuffle")
+	td := &TipsData{Tips: []Tip{{ID: "1"}, {ID: "2"}, {ID: "3"}}}
+
+	state, err := loadShuffleState(path)
+	if err != nil {
+		t.Fatalf("loadShuffleState failed: %v", err)
+	}
+	first := dealTips(t, state, td, tipFilter{}, 1)[0]
+
+	knownAt := time.Now()
+	for i := range td.Tips {
+		if td.Tips[i].ID != first {
+			td.Tips[i].KnownAt = &knownAt
+			break
+		}
+	}
+	td.Tips = append(td.Tips, Tip{ID: "4"})
+
+	dealt := dealTips(t, state, td, tipFilter{}, 2)
+	seen := newIDSet(dealt)
+	if _, ok := seen["4"]; !ok {
+		t.Errorf("Expected the new tip in the current round, got %v", dealt)
+	}
+	for _, id := range dealt {
+		if tip := td.findTip(id); tip.isKnown() || id == first {
+			t.Errorf("Expected known and already dealt tips to be skipped, got %v", dealt)
+		}
+	}
+}
+
+func TestLoadShuffleState_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tips.json.shuffle")
+	if err := os.WriteFile(path, []byte("{not json"), 0600); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	state, err := loadShuffleState(path)
+	if err != nil {
+		t.Fatalf("Expected a fresh state, got %v", err)
+	}
+	if tip, err := state.pick(&TipsData{Tips: []Tip{{ID: "1"}}}, tipFilter{}); err != nil || tip == nil {
+		t.Errorf("Expected tip 1, got %+v, %v", tip, err)
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// Spaced-repetition scheduling follows SM-2: each passing review multiplies
+// the interval by the tip's ease, and the grade nudges the ease up or down.
+const (
+	defaultEase = 2.5
+	minEase     = 1.3
+
+	// A tip graded "again" comes back within the same session.
+	relearnDelay = 10 * time.Minute
+)
+
+type grade int
+
+const (
+	gradeAgain grade = iota + 1
+	gradeHard
+	gradeGood
+	gradeEasy
+)
+
+// quality maps a grade onto SM-2's 0-5 response quality.
+func (g grade) quality() float64 {
+	switch g {
+	case gradeAgain:
+		return 1
+	case gradeHard:
+		return 3
+	case gradeGood:
+		return 4
+	default:
+		return 5
+	}
+}
+
+func (tip *Tip) ease() float64 {
+	if tip.Ease == 0 {
+		return defaultEase
+	}
+	return tip.Ease
+}
+
+func (tip *Tip) isDue(now time.Time) bool {
+	return tip.DueAt != nil && !tip.DueAt.After(now)
+}
+
+// review reschedules the tip after it was graded at now.
+func (tip *Tip) review(g grade, now time.Time) {
+	tip.Reviews++
+
+	if g == gradeAgain {
+		tip.IntervalDays = 0
+		due := now.Add(relearnDelay)
+		tip.DueAt = &due
+		return
+	}
+
+	switch tip.IntervalDays {
+	case 0:
+		tip.IntervalDays = 1
+	case 1:
+		tip.IntervalDays = 6
+	default:
+		tip.IntervalDays = int(math.Round(float64(tip.IntervalDays) * tip.ease()))
+	}
+
+	q := 5 - g.quality()
+	tip.Ease = math.Max(minEase, tip.ease()+0.1-q*(0.08+q*0.02))
+
+	due := now.AddDate(0, 0, tip.IntervalDays)
+	tip.DueAt = &due
+}
+
+// selectionTier ranks tips for srsStrategy: overdue tips first, then tips
+// that have never been reviewed, then tips scheduled for later.
+func (tip *Tip) selectionTier(now time.Time) int {
+	switch {
+	case tip.isDue(now):
+		return 0
+	case tip.DueAt == nil:
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTip_review(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name             string
+		grades           []grade
+		expectedInterval int
+		expectedEase     float64
+		expectedDue      time.Time
+	}{
+		{
+			name:             "first good review",
+			grades:           []grade{gradeGood},
+			expectedInterval: 1,
+			expectedEase:     2.5,
+			expectedDue:      now.AddDate(0, 0, 1),
+		},
+		{
+			name:             "second good review",
+			grades:           []grade{gradeGood, gradeGood},
+			expectedInterval: 6,
+			expectedEase:     2.5,
+			expectedDue:      now.AddDate(0, 0, 6),
+		},
+		{
+			name:             "third good review multiplies by ease",
+			grades:           []grade{gradeGood, gradeGood, gradeGood},
+			expectedInterval: 15,
+			expectedEase:     2.5,
+			expectedDue:      now.AddDate(0, 0, 15),
+		},
+		{
+			name:             "easy raises ease",
+			grades:           []grade{gradeEasy},
+			expectedInterval: 1,
+			expectedEase:     2.6,
+			expectedDue:      now.AddDate(0, 0, 1),
+		},
+		{
+			name:             "hard lowers ease",
+			grades:           []grade{gradeHard},
+			expectedInterval: 1,
+			expectedEase:     2.36,
+			expectedDue:      now.AddDate(0, 0, 1),
+		},
+		{
+			name:             "again restarts without changing ease",
+			grades:           []grade{gradeGood, gradeGood, gradeAgain},
+			expectedInterval: 0,
+			expectedEase:     2.5,
+			expectedDue:      now.Add(relearnDelay),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tip := Tip{ID: "1"}
+			for _, g := range tt.grades {
+				tip.review(g, now)
+			}
+
+			if tip.Reviews != len(tt.grades) {
+				t.Errorf("Expected %d reviews, got %d", len(tt.grades), tip.Reviews)
+			}
+			if tip.IntervalDays != tt.expectedInterval {
+				t.Errorf("Expected interval %d, got %d", tt.expectedInterval, tip.IntervalDays)
+			}
+			if diff := tip.ease() - tt.expectedEase; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("Expected ease %.2f, got %.2f", tt.expectedEase, tip.ease())
+			}
+			if tip.DueAt == nil || !tip.DueAt.Equal(tt.expectedDue) {
+				t.Errorf("Expected due %v, got %v", tt.expectedDue, tip.DueAt)
+			}
+		})
+	}
+}
+
+func TestTip_reviewMinimumEase(t *testing.T) {
+	tip := Tip{ID: "1"}
+	for i := 0; i < 20; i++ {
+		tip.review(gradeHard, time.Now())
+	}
+	if tip.ease() != minEase {
+		t.Errorf("Expected ease to bottom out at %.1f, got %.2f", minEase, tip.ease())
+	}
+}
@@ -0,0 +1,468 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/gofrs/flock"
+)
+
+type TipStore interface {
+	Load() (*TipsData, error)
+	Save(tipsData *TipsData) error
+	Add(tips ...Tip) error
+	Update(tips ...Tip) error
+	Remove(ids ...string) (int, error)
+	QueryByTopic(topics []string) ([]Tip, error)
+	Clear() error
+	Close() error
+}
+
+func storeKind() (string, error) {
+	if storeFlag != "" {
+		return storeFlag, nil
+	}
+	if kind := os.Getenv("TIPS_STORE"); kind != "" {
+		return kind, nil
+	}
+	if profileStore != "" {
+		return profileStore, nil
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	if config.Store != "" {
+		return config.Store, nil
+	}
+	return "json", nil
+}
+
+// validateStoreKind rejects stores openStore doesn't know.
+func validateStoreKind(kind string) error {
+	if kind != "json" && kind != "sqlite" {
+		return fmt.Errorf("unsupported store: %s. Supported stores: json, sqlite", kind)
+	}
+	return nil
+}
+
+var openStore = func() (TipStore, error) {
+	kind, err := storeKind()
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case "json":
+		filePath, err := getTipsFilePath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tips file path: %w", err)
+		}
+		store := newJSONFileStore(filePath)
+		// The journal holds tip contents in plain text, so encrypted files don't get one.
+		if _, encrypted := store.diskFormat(); encrypted {
+			return store, nil
+		}
+		return newJournaledStore(newIndexedStore(store, searchIndexPath(filePath)), journalPath(filePath)), nil
+	case "sqlite":
+		dbPath, err := getTipsDBPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get tips database path: %w", err)
+		}
+		store, err := newSQLiteStore(dbPath)
+		if err != nil {
+			return nil, err
+		}
+		return newJournaledStore(newIndexedStore(store, searchIndexPath(dbPath)), journalPath(dbPath)), nil
+	default:
+		return nil, validateStoreKind(kind)
+	}
+}
+
+type jsonFileStore struct {
+	path string
+}
+
+func newJSONFileStore(path string) *jsonFileStore {
+	return &jsonFileStore{path: path}
+}
+
+// Load doesn't take the lock: writes are atomic renames, so readers always
+// see a complete file.
+func (s *jsonFileStore) Load() (*TipsData, error) {
+	tipsData, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	tipsData.markLoaded()
+	return tipsData, nil
+}
+
+func (s *jsonFileStore) Save(tipsData *TipsData) error {
+	if tipsData == nil {
+		return fmt.Errorf("tips data cannot be nil")
+	}
+
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current := &TipsData{}
+	if tipsData.loaded != nil {
+		if current, err = s.load(); err != nil {
+			return err
+		}
+	}
+
+	merged := tipsData.mergeInto(current)
+	if err := s.write(merged); err != nil {
+		return err
+	}
+
+	tipsData.Tips = merged.Tips
+	tipsData.markLoaded()
+	return nil
+}
+
+func (s *jsonFileStore) lock() (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tips directory: %w", err)
+	}
+
+	fileLock := flock.New(s.path + ".lock")
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("failed to lock tips file: %w", err)
+	}
+
+	return func() { fileLock.Unlock() }, nil
+}
+
+func (s *jsonFileStore) load() (*TipsData, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return &TipsData{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tips file: %w", err)
+	}
+
+	if len(data) == 0 {
+		if recovered, err := s.recoverFromBackup(); err == nil {
+			return recovered, nil
+		}
+		return &TipsData{}, nil
+	}
+
+	tipsData, err := decodeTipsData(data)
+	if err != nil {
+		// A key problem isn't damage, so don't restore over the file.
+		if isEncrypted(data) {
+			return nil, err
+		}
+		if recovered, recoverErr := s.recoverFromBackup(); recoverErr == nil {
+			return recovered, nil
+		}
+		return nil, fmt.Errorf("failed to parse tips file: %w", err)
+	}
+
+	return tipsData, nil
+}
+
+func (s *jsonFileStore) write(tipsData *TipsData) error {
+	if err := checkSchemaWritable(tipsData.SchemaVersion); err != nil {
+		return err
+	}
+	version, encrypted := s.diskFormat()
+	if err := checkSchemaWritable(version); err != nil {
+		return err
+	}
+
+	data, err := s.encode(tipsData, encrypted)
+	if err != nil {
+		return err
+	}
+
+	if err := s.rotateBackup(); err != nil {
+		return fmt.Errorf("failed to back up tips file: %w", err)
+	}
+
+	if err := writeFileAtomic(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write tips file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *jsonFileStore) encode(tipsData *TipsData, encrypt bool) ([]byte, error) {
+	tipsData.SchemaVersion = currentSchemaVersion
+	data, err := json.MarshalIndent(tipsData, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tips data: %w", err)
+	}
+
+	if encrypt {
+		if data, err = encryptTipsData(data, tipsData.SchemaVersion); err != nil {
+			return nil, fmt.Errorf("failed to encrypt tips data: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// diskFormat reports the schema version of the file on disk and whether it's
+// encrypted, so writes keep the existing format.
+func (s *jsonFileStore) diskFormat() (int, bool) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return 0, false
+	}
+	version, _ := readSchemaVersion(data)
+	return version, isEncrypted(data)
+}
+
+func (s *jsonFileStore) backupPath() string {
+	return s.path + ".bak"
+}
+
+// rotateBackup copies the current tips file to the backup, but only if it
+// parses, so a corrupted file never replaces a good backup.
+func (s *jsonFileStore) rotateBackup() error {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(data) == 0 {
+		return nil
+	}
+	if _, err := decodeTipsData(data); err != nil {
+		return nil
+	}
+
+	return writeFileAtomic(s.backupPath(), data, 0600)
+}
+
+func (s *jsonFileStore) recoverFromBackup() (*TipsData, error) {
+	data, err := os.ReadFile(s.backupPath())
+	if err != nil {
+		return nil, err
+	}
+
+	tipsData, err := decodeTipsData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeFileAtomic(s.path, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to restore tips file from backup: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: %s was damaged; restored %d tips from %s\n", s.path, len(tipsData.Tips), s.backupPath())
+	return tipsData, nil
+}
+
+func decodeTipsData(data []byte) (*TipsData, error) {
+	var err error
+	if isEncrypted(data) {
+		if data, err = decryptTipsData(data); err != nil {
+			return nil, err
+		}
+	}
+
+	version, err := readSchemaVersion(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if data, err = migrateSchema(data, version); err != nil {
+		return nil, err
+	}
+
+	var tipsData TipsData
+	if err := json.Unmarshal(data, &tipsData); err != nil {
+		return nil, err
+	}
+	return &tipsData, nil
+}
+
+func (s *jsonFileStore) Add(tips ...Tip) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tipsData, err := s.load()
+	if err != nil {
+		return err
+	}
+	tipsData.Tips = append(tipsData.Tips, tips...)
+	return s.write(tipsData)
+}
+
+func (s *jsonFileStore) Update(tips ...Tip) error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	tipsData, err := s.load()
+	if err != nil {
+		return err
+	}
+	if tipsData.updateTips(tips) == 0 {
+		return nil
+	}
+	return s.write(tipsData)
+}
+
+func (s *jsonFileStore) Remove(ids ...string) (int, error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	tipsData, err := s.load()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, id := range ids {
+		if tipsData.removeTip(id) {
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, s.write(tipsData)
+}
+
+func (s *jsonFileStore) QueryByTopic(topics []string) ([]Tip, error) {
+	tipsData, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	return tipsData.filterByTopic(topics), nil
+}
+
+func (s *jsonFileStore) Clear() error {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// Encryption is only known from the file itself, so an encrypted file is
+	// emptied rather than deleted, or the next write would be plain text.
+	if _, encrypted := s.diskFormat(); encrypted {
+		return s.write(&TipsData{})
+	}
+
+	if err := s.rotateBackup(); err != nil {
+		return fmt.Errorf("failed to back up tips file: %w", err)
+	}
+
+	if err := os.Remove(s.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete tips file: %w", err)
+	}
+	return nil
+}
+
+func (s *jsonFileStore) Close() error {
+	return nil
+}
+
+type memoryStore struct {
+	mu   sync.Mutex
+	tips []Tip
+}
+
+func newMemoryStore(tips ...Tip) *memoryStore {
+	return &memoryStore{tips: append([]Tip(nil), tips...)}
+}
+
+func (s *memoryStore) Load() (*TipsData, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tipsData := &TipsData{Tips: append([]Tip(nil), s.tips...)}
+	tipsData.markLoaded()
+	return tipsData, nil
+}
+
+func (s *memoryStore) Save(tipsData *TipsData) error {
+	if tipsData == nil {
+		return fmt.Errorf("tips data cannot be nil")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	merged := tipsData.mergeInto(&TipsData{Tips: s.tips})
+	s.tips = merged.Tips
+	tipsData.Tips = append([]Tip(nil), merged.Tips...)
+	tipsData.markLoaded()
+	return nil
+}
+
+func (s *memoryStore) Add(tips ...Tip) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tips = append(s.tips, tips...)
+	return nil
+}
+
+func (s *memoryStore) Update(tips ...Tip) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tipsData := &TipsData{Tips: s.tips}
+	tipsData.updateTips(tips)
+	return nil
+}
+
+func (s *memoryStore) Remove(ids ...string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tipsData := &TipsData{Tips: s.tips}
+	removed := 0
+	for _, id := range ids {
+		if tipsData.removeTip(id) {
+			removed++
+		}
+	}
+	s.tips = tipsData.Tips
+	return removed, nil
+}
+
+func (s *memoryStore) QueryByTopic(topics []string) ([]Tip, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tipsData := &TipsData{Tips: s.tips}
+	return append([]Tip(nil), tipsData.filterByTopic(topics)...), nil
+}
+
+func (s *memoryStore) Clear() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tips = nil
+	return nil
+}
+
+func (s *memoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// Tips are stored whole as JSON in the data column so new Tip fields don't
+// need a table change; topic and created_at are copied out for indexing.
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS tips (
+	id         TEXT PRIMARY KEY,
+	topic      TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	data       TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_tips_topic ON tips(topic);
+CREATE INDEX IF NOT EXISTS idx_tips_created_at ON tips(created_at);
+`
+
+type sqliteStore struct {
+	db      *sql.DB
+	version int
+}
+
+func newSQLiteStore(path string) (*sqliteStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tips directory: %w", err)
+	}
+
+	dsn := url.URL{Scheme: "file", Path: path, RawQuery: "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tips database: %w", err)
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialise tips database: %w", err)
+	}
+
+	store := &sqliteStore{db: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// The schema version lives in PRAGMA user_version. Older rows are upgraded
+// in place with the same migrations as the JSON file.
+func (s *sqliteStore) migrate() error {
+	if err := s.db.QueryRow("PRAGMA user_version").Scan(&s.version); err != nil {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if s.version >= currentSchemaVersion {
+		return nil
+	}
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT data FROM tips ORDER BY created_at, rowid")
+		if err != nil {
+			return fmt.Errorf("failed to query tips: %w", err)
+		}
+
+		var raw []json.RawMessage
+		for rows.Next() {
+			var data string
+			if err := rows.Scan(&data); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to read tip: %w", err)
+			}
+			raw = append(raw, json.RawMessage(data))
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("failed to query tips: %w", err)
+		}
+
+		if len(raw) > 0 {
+			doc, err := json.Marshal(map[string]any{"schema_version": s.version, "tips": raw})
+			if err != nil {
+				return fmt.Errorf("failed to migrate tips: %w", err)
+			}
+			tipsData, err := decodeTipsData(doc)
+			if err != nil {
+				return fmt.Errorf("failed to migrate tips: %w", err)
+			}
+			if err := updateTips(tx, tipsData.Tips); err != nil {
+				return err
+			}
+		}
+
+		if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", currentSchemaVersion)); err != nil {
+			return fmt.Errorf("failed to update schema version: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.version = currentSchemaVersion
+	return nil
+}
+
+func (s *sqliteStore) Load() (*TipsData, error) {
+	tips, err := s.queryTips("SELECT data FROM tips ORDER BY created_at, rowid")
+	if err != nil {
+		return nil, err
+	}
+
+	tipsData := &TipsData{SchemaVersion: s.version, Tips: tips}
+	tipsData.markLoaded()
+	return tipsData, nil
+}
+
+func (s *sqliteStore) Save(tipsData *TipsData) error {
+	if tipsData == nil {
+		return fmt.Errorf("tips data cannot be nil")
+	}
+	if err := checkSchemaWritable(s.version); err != nil {
+		return err
+	}
+
+	err := s.inTx(func(tx *sql.Tx) error {
+		if tipsData.loaded == nil {
+			if _, err := tx.Exec("DELETE FROM tips"); err != nil {
+				return fmt.Errorf("failed to clear tips: %w", err)
+			}
+			return insertTips(tx, "INSERT OR REPLACE", tipsData.Tips)
+		}
+
+		added, updated, removed := tipsData.delta()
+		if len(removed) > 0 {
+			if _, err := tx.Exec("DELETE FROM tips WHERE id IN ("+placeholders(len(removed))+")", stringArgs(removed)...); err != nil {
+				return fmt.Errorf("failed to remove tips: %w", err)
+			}
+		}
+		if err := insertTips(tx, "INSERT OR IGNORE", added); err != nil {
+			return err
+		}
+		if err := updateTips(tx, updated); err != nil {
+			return err
+		}
+
+		tips, err := queryTips(tx, "SELECT data FROM tips ORDER BY created_at, rowid")
+		if err != nil {
+			return err
+		}
+		tipsData.Tips = tips
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	tipsData.markLoaded()
+	return nil
+}
+
+func (s *sqliteStore) Add(tips ...Tip) error {
+	if err := checkSchemaWritable(s.version); err != nil {
+		return err
+	}
+	return s.inTx(func(tx *sql.Tx) error {
+		return insertTips(tx, "INSERT OR REPLACE", tips)
+	})
+}
+
+func (s *sqliteStore) Update(tips ...Tip) error {
+	if err := checkSchemaWritable(s.version); err != nil {
+		return err
+	}
+	return s.inTx(func(tx *sql.Tx) error {
+		return updateTips(tx, tips)
+	})
+}
+
+func (s *sqliteStore) Remove(ids ...string) (int, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := checkSchemaWritable(s.version); err != nil {
+		return 0, err
+	}
+
+	result, err := s.db.Exec("DELETE FROM tips WHERE id IN ("+placeholders(len(ids))+")", stringArgs(ids)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove tips: %w", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove tips: %w", err)
+	}
+	return int(removed), nil
+}
+
+// QueryByTopic selects plain topics and their children in SQL. Glob patterns
+// are matched in Go.
+func (s *sqliteStore) QueryByTopic(topics []string) ([]Tip, error) {
+	if len(topics) == 0 {
+		return s.queryTips("SELECT data FROM tips ORDER BY created_at, rowid")
+	}
+
+	var conditions []string
+	var args []any
+	for _, topic := range topics {
+		pattern := normalizeTopicPattern(topic)
+		if isTopicGlob(pattern) {
+			tips, err := s.queryTips("SELECT data FROM tips ORDER BY created_at, rowid")
+			if err != nil {
+				return nil, err
+			}
+			return (&TipsData{Tips: tips}).filterByTopic(topics), nil
+		}
+		conditions = append(conditions, `topic = ? COLLATE NOCASE OR topic LIKE ? ESCAPE '\'`)
+		args = append(args, pattern, escapeLike(pattern)+"/%")
+	}
+	return s.queryTips("SELECT data FROM tips WHERE "+strings.Join(conditions, " OR ")+" ORDER BY created_at, rowid", args...)
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func (s *sqliteStore) Clear() error {
+	if err := checkSchemaWritable(s.version); err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("DELETE FROM tips"); err != nil {
+		return fmt.Errorf("failed to clear tips: %w", err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqliteStore) queryTips(query string, args ...any) ([]Tip, error) {
+	return queryTips(s.db, query, args...)
+}
+
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func queryTips(q querier, query string, args ...any) ([]Tip, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tips: %w", err)
+	}
+	defer rows.Close()
+
+	var tips []Tip
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read tip: %w", err)
+		}
+
+		var tip Tip
+		if err := json.Unmarshal([]byte(data), &tip); err != nil {
+			return nil, fmt.Errorf("failed to parse tip: %w", err)
+		}
+		tips = append(tips, tip)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query tips: %w", err)
+	}
+	return tips, nil
+}
+
+func (s *sqliteStore) inTx(fn func(tx *sql.Tx) error) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+func insertTips(tx *sql.Tx, verb string, tips []Tip) error {
+	if len(tips) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare(verb + " INTO tips (id, topic, created_at, data) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, tip := range tips {
+		data, err := json.Marshal(tip)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tip: %w", err)
+		}
+		if _, err := stmt.Exec(tip.ID, tip.Topic, tip.CreatedAt.UnixNano(), string(data)); err != nil {
+			return fmt.Errorf("failed to insert tip: %w", err)
+		}
+	}
+	return nil
+}
+
+func updateTips(tx *sql.Tx, tips []Tip) error {
+	if len(tips) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.Prepare("UPDATE tips SET topic = ?, created_at = ?, data = ? WHERE id = ?")
+	if err != nil {
+		return fmt.Errorf("failed to prepare update: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, tip := range tips {
+		data, err := json.Marshal(tip)
+		if err != nil {
+			return fmt.Errorf("failed to marshal tip: %w", err)
+		}
+		if _, err := stmt.Exec(tip.Topic, tip.CreatedAt.UnixNano(), string(data), tip.ID); err != nil {
+			return fmt.Errorf("failed to update tip: %w", err)
+		}
+	}
+	return nil
+}
+
+func migrateJSONToSQLite(jsonPath, dbPath string) (int, error) {
+	tipsData, err := newJSONFileStore(jsonPath).Load()
+	if err != nil {
+		return 0, err
+	}
+
+	store, err := newSQLiteStore(dbPath)
+	if err != nil {
+		return 0, err
+	}
+	defer store.Close()
+
+	if err := store.Add(tipsData.Tips...); err != nil {
+		return 0, err
+	}
+	return len(tipsData.Tips), nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+func stringArgs(values []string) []any {
+	args := make([]any, len(values))
+	for i, v := range values {
+		args[i] = v
+	}
+	return args
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSQLiteStore_Indexes(t *testing.T) {
+	store, err := newSQLiteStore(filepath.Join(t.TempDir(), "tips.db"))
+	if err != nil {
+		t.Fatalf("Failed to open sqlite store: %v", err)
+	}
+	defer store.Close()
+
+	for _, index := range []string{"idx_tips_topic", "idx_tips_created_at"} {
+		var name string
+		err := store.db.QueryRow("SELECT name FROM sqlite_master WHERE type = 'index' AND name = ?", index).Scan(&name)
+		if err != nil {
+			t.Errorf("Index %s not found: %v", index, err)
+		}
+	}
+}
+
+func TestSQLiteStore_EscapesPath(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "my tips?#100%")
+	path := filepath.Join(dir, "tips.db")
+	store, err := newSQLiteStore(path)
+	if err != nil {
+		t.Fatalf("Failed to open sqlite store: %v", err)
+	}
+	defer store.Close()
+
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "one"}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Expected the database at %s: %v", path, err)
+	}
+
+	var mode string
+	if err := store.db.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil || mode != "wal" {
+		t.Errorf("Expected WAL mode, got %q (%v)", mode, err)
+	}
+}
+
+func TestSQLiteStore_OrdersByCreatedAt(t *testing.T) {
+	store, err := newSQLiteStore(filepath.Join(t.TempDir(), "tips.db"))
+	if err != nil {
+		t.Fatalf("Failed to open sqlite store: %v", err)
+	}
+	defer store.Close()
+
+	now := time.Now()
+	err = store.Add(
+		Tip{ID: "newer", Topic: "git", Content: "newer tip", CreatedAt: now},
+		Tip{ID: "older", Topic: "git", Content: "older tip", CreatedAt: now.Add(-time.Hour)},
+	)
+	if err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	tips, err := store.QueryByTopic([]string{"git"})
+	if err != nil {
+		t.Fatalf("QueryByTopic failed: %v", err)
+	}
+
+	if len(tips) != 2 || tips[0].ID != "older" || tips[1].ID != "newer" {
+		t.Errorf("Expected tips ordered oldest first, got %+v", tips)
+	}
+
+	if !tips[1].CreatedAt.Equal(now) {
+		t.Errorf("Expected created_at %v to round-trip, got %v", now, tips[1].CreatedAt)
+	}
+}
+
+func TestMigrateJSONToSQLite(t *testing.T) {
+	tmpDir := t.TempDir()
+	jsonPath := filepath.Join(tmpDir, ".tips.json")
+	dbPath := filepath.Join(tmpDir, ".tips.db")
+
+	data, err := os.ReadFile(filepath.Join("testdata", "sample_tips.json"))
+	if err != nil {
+		t.Fatalf("Failed to read sample tips: %v", err)
+	}
+	if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write tips file: %v", err)
+	}
+
+	var expected TipsData
+	if err := json.Unmarshal(data, &expected); err != nil {
+		t.Fatalf("Failed to parse sample tips: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		migrated, err := migrateJSONToSQLite(jsonPath, dbPath)
+		if err != nil {
+			t.Fatalf("Migration %d failed: %v", i, err)
+		}
+		if migrated != len(expected.Tips) {
+			t.Errorf("Expected %d tips migrated, got %d", len(expected.Tips), migrated)
+		}
+	}
+
+	store, err := newSQLiteStore(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to open sqlite store: %v", err)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+
+	if len(tipsData.Tips) != len(expected.Tips) {
+		t.Fatalf("Expected %d tips after repeated migration, got %d", len(expected.Tips), len(tipsData.Tips))
+	}
+
+	for i, tip := range tipsData.Tips {
+		if tip.ID != expected.Tips[i].ID || tip.Content != expected.Tips[i].Content {
+			t.Errorf("Tip %d mismatch: expected %+v, got %+v", i, expected.Tips[i], tip)
+		}
+	}
+
+	if _, err := os.Stat(jsonPath); err != nil {
+		t.Errorf("JSON file should be left in place: %v", err)
+	}
+}
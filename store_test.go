@@ -0,0 +1,450 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func useStore(t testing.TB, store TipStore) {
+	t.Helper()
+	originalOpenStore := openStore
+	openStore = func() (TipStore, error) { return store, nil }
+	t.Cleanup(func() { openStore = originalOpenStore })
+}
+
+func useMemoryStore(t testing.TB, tips ...Tip) *memoryStore {
+	t.Helper()
+	store := newMemoryStore(tips...)
+	useStore(t, store)
+	return store
+}
+
+func testStores(t *testing.T) map[string]func() TipStore {
+	return map[string]func() TipStore{
+		"json": func() TipStore {
+			return newJSONFileStore(filepath.Join(t.TempDir(), "tips.json"))
+		},
+		"sqlite": func() TipStore {
+			store, err := newSQLiteStore(filepath.Join(t.TempDir(), "tips.db"))
+			if err != nil {
+				t.Fatalf("Failed to open sqlite store: %v", err)
+			}
+			t.Cleanup(func() { store.Close() })
+			return store
+		},
+		"memory": func() TipStore {
+			return newMemoryStore()
+		},
+	}
+}
+
+func TestTipStore_QueryByTopicHierarchy(t *testing.T) {
+	tips := []Tip{
+		{ID: "1", Topic: "go", Content: "go tip", CreatedAt: time.Now()},
+		{ID: "2", Topic: "Go/Testing", Content: "testing tip", CreatedAt: time.Now()},
+		{ID: "3", Topic: "go/concurrency/channels", Content: "channels tip", CreatedAt: time.Now()},
+		{ID: "4", Topic: "golang", Content: "not a child", CreatedAt: time.Now()},
+		{ID: "5", Topic: "k8s/go_client", Content: "k8s tip", CreatedAt: time.Now()},
+	}
+
+	tests := []struct {
+		topics   []string
+		expected string
+	}{
+		{topics: []string{"go"}, expected: "1,2,3"},
+		{topics: []string{"GO/testing"}, expected: "2"},
+		{topics: []string{"go/*"}, expected: "2,3"},
+		{topics: []string{"go*"}, expected: "1,2,3,4"},
+		{topics: []string{"k8s/go%"}, expected: ""},
+		{topics: []string{"go/testing", "k8s"}, expected: "2,5"},
+	}
+
+	for name, newStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			if err := store.Add(tips...); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+
+			for _, tt := range tests {
+				matched, err := store.QueryByTopic(tt.topics)
+				if err != nil {
+					t.Fatalf("QueryByTopic failed: %v", err)
+				}
+				ids := make([]string, len(matched))
+				for i, tip := range matched {
+					ids[i] = tip.ID
+				}
+				sort.Strings(ids)
+				if got := strings.Join(ids, ","); got != tt.expected {
+					t.Errorf("QueryByTopic(%v): expected %q, got %q", tt.topics, tt.expected, got)
+				}
+			}
+		})
+	}
+}
+
+func TestTipStore_AddRemoveQuery(t *testing.T) {
+	for name, newStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+
+			tips := []Tip{
+				{ID: "1", Topic: "git", Content: "git tip", CreatedAt: time.Now()},
+				{ID: "2", Topic: "vim", Content: "vim tip", CreatedAt: time.Now()},
+				{ID: "3", Topic: "git", Content: "another git tip", CreatedAt: time.Now()},
+			}
+			if err := store.Add(tips...); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+
+			gitTips, err := store.QueryByTopic([]string{"git"})
+			if err != nil {
+				t.Fatalf("QueryByTopic failed: %v", err)
+			}
+			if len(gitTips) != 2 {
+				t.Errorf("Expected 2 git tips, got %d", len(gitTips))
+			}
+
+			allTips, err := store.QueryByTopic(nil)
+			if err != nil {
+				t.Fatalf("QueryByTopic failed: %v", err)
+			}
+			if len(allTips) != 3 {
+				t.Errorf("Expected 3 tips, got %d", len(allTips))
+			}
+
+			knownAt := time.Now().UTC().Truncate(time.Second)
+			updated := tips[1]
+			updated.KnownAt = &knownAt
+			if err := store.Update(updated, Tip{ID: "non-existent"}); err != nil {
+				t.Fatalf("Update failed: %v", err)
+			}
+
+			vimTips, err := store.QueryByTopic([]string{"vim"})
+			if err != nil {
+				t.Fatalf("QueryByTopic failed: %v", err)
+			}
+			if len(vimTips) != 1 || vimTips[0].KnownAt == nil || !vimTips[0].KnownAt.Equal(knownAt) {
+				t.Errorf("Expected vim tip known at %v, got %+v", knownAt, vimTips)
+			}
+
+			removed, err := store.Remove("1", "non-existent")
+			if err != nil {
+				t.Fatalf("Remove failed: %v", err)
+			}
+			if removed != 1 {
+				t.Errorf("Expected 1 tip removed, got %d", removed)
+			}
+
+			tipsData, err := store.Load()
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if len(tipsData.Tips) != 2 {
+				t.Errorf("Expected 2 tips after removal, got %d", len(tipsData.Tips))
+			}
+			for _, tip := range tipsData.Tips {
+				if tip.ID == "1" {
+					t.Error("Tip with ID '1' should have been removed")
+				}
+			}
+		})
+	}
+}
+
+func TestTipStore_SaveAndClear(t *testing.T) {
+	for name, newStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+
+			if err := store.Save(nil); err == nil {
+				t.Error("Expected error for nil tips data")
+			}
+
+			tipsData := &TipsData{
+				Tips: []Tip{
+					{ID: "1", Topic: "test", Content: "test content", CreatedAt: time.Now()},
+				},
+			}
+			if err := store.Save(tipsData); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			loaded, err := store.Load()
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+			if len(loaded.Tips) != 1 || loaded.Tips[0].Content != "test content" {
+				t.Errorf("Unexpected tips after save: %+v", loaded.Tips)
+			}
+
+			if err := store.Clear(); err != nil {
+				t.Fatalf("Clear failed: %v", err)
+			}
+
+			loaded, err = store.Load()
+			if err != nil {
+				t.Fatalf("Load after clear failed: %v", err)
+			}
+			if len(loaded.Tips) != 0 {
+				t.Errorf("Expected no tips after clear, got %d", len(loaded.Tips))
+			}
+
+			if err := store.Clear(); err != nil {
+				t.Errorf("Clearing an empty store should not fail: %v", err)
+			}
+		})
+	}
+}
+
+func TestJSONFileStore_ClearRemovesFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tips.json")
+	store := newJSONFileStore(filePath)
+
+	if err := store.Add(Tip{ID: "1", Topic: "git", Content: "tip", CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+
+	if _, err := os.Stat(filePath); err != nil {
+		t.Fatalf("Tips file was not created: %v", err)
+	}
+
+	if err := store.Clear(); err != nil {
+		t.Fatalf("Clear failed: %v", err)
+	}
+
+	if _, err := os.Stat(filePath); !os.IsNotExist(err) {
+		t.Error("Tips file should have been deleted")
+	}
+}
+
+func TestMemoryStore_LoadReturnsCopy(t *testing.T) {
+	store := newMemoryStore(Tip{ID: "1", Topic: "git", Content: "tip", CreatedAt: time.Now()})
+
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	tipsData.removeTip("1")
+
+	reloaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(reloaded.Tips) != 1 {
+		t.Error("Mutating loaded data should not affect the store")
+	}
+}
+
+func TestStoreKind(t *testing.T) {
+	originalStoreFlag := storeFlag
+	defer func() { storeFlag = originalStoreFlag }()
+
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		config   string
+		expected string
+	}{
+		{name: "default", expected: "json"},
+		{name: "config", config: `store = "sqlite"`, expected: "sqlite"},
+		{name: "env overrides config", env: "sqlite", config: `store = "json"`, expected: "sqlite"},
+		{name: "flag overrides env", flag: "json", env: "sqlite", expected: "json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setTestHome(t, t.TempDir())
+			writeTestConfig(t, tt.config)
+			storeFlag = tt.flag
+			t.Setenv("TIPS_STORE", tt.env)
+
+			kind, err := storeKind()
+			if err != nil {
+				t.Fatalf("storeKind failed: %v", err)
+			}
+			if kind != tt.expected {
+				t.Errorf("Expected store kind %q, got %q", tt.expected, kind)
+			}
+		})
+	}
+}
+
+func TestOpenStore_Unsupported(t *testing.T) {
+	originalStoreFlag := storeFlag
+	defer func() { storeFlag = originalStoreFlag }()
+
+	storeFlag = "postgres"
+	if _, err := openStore(); err == nil {
+		t.Error("Expected error for unsupported store")
+	}
+}
+
+func TestJSONFileStore_RotatesBackup(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tips.json")
+	store := newJSONFileStore(filePath)
+
+	first := &TipsData{Tips: []Tip{{ID: "1", Topic: "git", Content: "first", CreatedAt: time.Now()}}}
+	if err := store.Save(first); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if _, err := os.Stat(store.backupPath()); !os.IsNotExist(err) {
+		t.Error("No backup should exist before the file is overwritten")
+	}
+
+	second := &TipsData{Tips: []Tip{{ID: "2", Topic: "git", Content: "second", CreatedAt: time.Now()}}}
+	if err := store.Save(second); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	backup, err := newJSONFileStore(store.backupPath()).Load()
+	if err != nil {
+		t.Fatalf("Failed to load backup: %v", err)
+	}
+	if len(backup.Tips) != 1 || backup.Tips[0].ID != "1" {
+		t.Errorf("Expected backup to hold the previous version, got %+v", backup.Tips)
+	}
+}
+
+func TestJSONFileStore_RecoversFromBackup(t *testing.T) {
+	tests := []struct {
+		name    string
+		damaged string
+	}{
+		{name: "malformed file", damaged: `{"tips": [{"id": "1", "topic": "git"`},
+		{name: "truncated file", damaged: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "tips.json")
+			store := newJSONFileStore(filePath)
+
+			tipsData := &TipsData{Tips: []Tip{{ID: "1", Topic: "git", Content: "tip", CreatedAt: time.Now()}}}
+			if err := store.Save(tipsData); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+			if err := store.Save(tipsData); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			if err := os.WriteFile(filePath, []byte(tt.damaged), 0644); err != nil {
+				t.Fatalf("Failed to damage tips file: %v", err)
+			}
+
+			loaded, err := store.Load()
+			if err != nil {
+				t.Fatalf("Expected recovery from backup, got error: %v", err)
+			}
+			if len(loaded.Tips) != 1 || loaded.Tips[0].ID != "1" {
+				t.Errorf("Expected recovered tip, got %+v", loaded.Tips)
+			}
+
+			data, err := os.ReadFile(filePath)
+			if err != nil {
+				t.Fatalf("Failed to read restored file: %v", err)
+			}
+			if _, err := decodeTipsData(data); err != nil || len(data) == 0 {
+				t.Errorf("Expected tips file to be restored, got %q", data)
+			}
+		})
+	}
+}
+
+func TestJSONFileStore_CorruptFileWithoutBackup(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tips.json")
+	if err := os.WriteFile(filePath, []byte(`{"tips": [`), 0644); err != nil {
+		t.Fatalf("Failed to write tips file: %v", err)
+	}
+
+	if _, err := newJSONFileStore(filePath).Load(); err == nil {
+		t.Error("Expected parse error without a backup")
+	}
+}
+
+func TestJSONFileStore_CorruptFileDoesNotReplaceBackup(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "tips.json")
+	store := newJSONFileStore(filePath)
+
+	good := &TipsData{Tips: []Tip{{ID: "1", Topic: "git", Content: "tip", CreatedAt: time.Now()}}}
+	if err := store.Save(good); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := store.Save(good); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	if err := os.WriteFile(filePath, []byte("garbage"), 0644); err != nil {
+		t.Fatalf("Failed to damage tips file: %v", err)
+	}
+	if err := store.Save(&TipsData{}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	backup, err := newJSONFileStore(store.backupPath()).Load()
+	if err != nil {
+		t.Fatalf("Failed to load backup: %v", err)
+	}
+	if len(backup.Tips) != 1 {
+		t.Errorf("Expected good backup to be kept, got %+v", backup.Tips)
+	}
+}
+
+func TestTipStore_SaveMergesConcurrentChanges(t *testing.T) {
+	for name, newStore := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+
+			err := store.Add(
+				Tip{ID: "1", Topic: "git", Content: "tip 1", CreatedAt: time.Now()},
+				Tip{ID: "2", Topic: "git", Content: "tip 2", CreatedAt: time.Now()},
+			)
+			if err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+
+			stale, err := store.Load()
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+
+			if err := store.Add(Tip{ID: "3", Topic: "git", Content: "tip 3", CreatedAt: time.Now()}); err != nil {
+				t.Fatalf("Add failed: %v", err)
+			}
+
+			stale.removeTip("1")
+			stale.Tips = append(stale.Tips, Tip{ID: "4", Topic: "vim", Content: "tip 4", CreatedAt: time.Now()})
+			if err := store.Save(stale); err != nil {
+				t.Fatalf("Save failed: %v", err)
+			}
+
+			tipsData, err := store.Load()
+			if err != nil {
+				t.Fatalf("Load failed: %v", err)
+			}
+
+			ids := newIDSet(nil)
+			for _, tip := range tipsData.Tips {
+				ids[tip.ID] = struct{}{}
+			}
+			for _, id := range []string{"2", "3", "4"} {
+				if _, ok := ids[id]; !ok {
+					t.Errorf("Expected tip %s to be present", id)
+				}
+			}
+			if _, ok := ids["1"]; ok {
+				t.Error("Tip 1 should have been removed")
+			}
+			if len(stale.Tips) != 3 {
+				t.Errorf("Expected saved data to reflect merged state, got %d tips", len(stale.Tips))
+			}
+		})
+	}
+}
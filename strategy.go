@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	defaultStrategy = "srs"
+
+	// Ratings are kept small: each point doubles or halves a tip's weight.
+	minRating = -3
+	maxRating = 3
+)
+
+// A selectionStrategy decides which tip to show next.
+type selectionStrategy interface {
+	pick(td *TipsData, filter tipFilter) (*Tip, error)
+}
+
+var strategyNames = []string{"srs", "random", "shuffle", "weighted"}
+
+func getStrategyName(config *Config) string {
+	if strategyFlag != "" {
+		return strategyFlag
+	}
+	if shuffleFlag {
+		return "shuffle"
+	}
+	if name := os.Getenv("TIPS_STRATEGY"); name != "" {
+		return name
+	}
+	if config.Strategy != "" {
+		return config.Strategy
+	}
+	return defaultStrategy
+}
+
+func newSelectionStrategy() (selectionStrategy, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	rng := newTipRand()
+	switch name := getStrategyName(config); name {
+	case "srs":
+		return srsStrategy{rng: rng}, nil
+	case "random":
+		return randomStrategy{rng: rng}, nil
+	case "shuffle":
+		state, err := openShuffleState()
+		if err != nil {
+			return nil, err
+		}
+		state.rng = rng
+		return state, nil
+	case "weighted":
+		return weightedStrategy{
+			topicWeights: config.TopicWeights,
+			ageHalfLife:  time.Duration(config.AgeHalfLifeDays) * 24 * time.Hour,
+			rng:          rng,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported strategy: %s. Supported strategies: %s", name, strings.Join(strategyNames, ", "))
+	}
+}
+
+// tipRand draws from the generator seeded by --seed, or from the global one
+// when no seed was given.
+type tipRand struct {
+	seeded *rand.Rand
+}
+
+func newTipRand() tipRand {
+	if !seedSet {
+		return tipRand{}
+	}
+	return tipRand{seeded: rand.New(rand.NewSource(seedFlag))}
+}
+
+func (r tipRand) Float64() float64 {
+	if r.seeded == nil {
+		return rand.Float64()
+	}
+	return r.seeded.Float64()
+}
+
+func (r tipRand) Intn(n int) int {
+	if r.seeded == nil {
+		return rand.Intn(n)
+	}
+	return r.seeded.Intn(n)
+}
+
+func (r tipRand) Shuffle(n int, swap func(i, j int)) {
+	if r.seeded == nil {
+		rand.Shuffle(n, swap)
+		return
+	}
+	r.seeded.Shuffle(n, swap)
+}
+
+// pickWeighted picks a selectable tip with probability proportional to its
+// weight. Tips weighing zero are never picked.
+func (td *TipsData) pickWeighted(rng tipRand, filter tipFilter, weight func(tip *Tip) float64) *Tip {
+	selectable := newTipFilter(filter)
+
+	total := 0.0
+	for i := range td.Tips {
+		if selectable(&td.Tips[i]) {
+			total += weight(&td.Tips[i])
+		}
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	r := rng.Float64() * total
+	var last *Tip
+	for i := range td.Tips {
+		if !selectable(&td.Tips[i]) {
+			continue
+		}
+		if w := weight(&td.Tips[i]); w > 0 {
+			last = &td.Tips[i]
+			if r -= w; r < 0 {
+				return last
+			}
+		}
+	}
+	// Rounding can leave r just above zero after the last tip.
+	return last
+}
+
+type randomStrategy struct {
+	rng tipRand
+}
+
+func (s randomStrategy) pick(td *TipsData, filter tipFilter) (*Tip, error) {
+	return td.pickWeighted(s.rng, filter, func(tip *Tip) float64 { return 1 }), nil
+}
+
+// srsStrategy shows overdue tips first, then tips never reviewed, then tips
+// scheduled for later, picking at random within each group.
+type srsStrategy struct {
+	rng tipRand
+}
+
+func (s srsStrategy) pick(td *TipsData, filter tipFilter) (*Tip, error) {
+	now := time.Now()
+	selectable := newTipFilter(filter)
+
+	tier := -1
+	for i := range td.Tips {
+		if selectable(&td.Tips[i]) {
+			if t := td.Tips[i].selectionTier(now); tier == -1 || t < tier {
+				tier = t
+			}
+		}
+	}
+
+	return td.pickWeighted(s.rng, filter, func(tip *Tip) float64 {
+		if tip.selectionTier(now) == tier {
+			return 1
+		}
+		return 0
+	}), nil
+}
+
+// weightedStrategy favours tips by topic weight, age and rating. Topics
+// take the weight of their nearest weighted ancestor, or 1. A tip
+// ageHalfLife old is half as likely as a new one, and each rating point
+// doubles or halves a tip's chances.
+type weightedStrategy struct {
+	topicWeights map[string]float64
+	ageHalfLife  time.Duration
+	rng          tipRand
+}
+
+func (s weightedStrategy) pick(td *TipsData, filter tipFilter) (*Tip, error) {
+	now := time.Now()
+	return td.pickWeighted(s.rng, filter, func(tip *Tip) float64 { return s.weight(tip, now) }), nil
+}
+
+func (s weightedStrategy) weight(tip *Tip, now time.Time) float64 {
+	weight := s.topicWeight(tip.Topic)
+
+	if age := now.Sub(tip.CreatedAt); s.ageHalfLife > 0 && age > 0 {
+		weight /= 1 + float64(age)/float64(s.ageHalfLife)
+	}
+
+	return weight * math.Pow(2, float64(tip.Rating))
+}
+
+func (s weightedStrategy) topicWeight(topic string) float64 {
+	topic = strings.ToLower(strings.Trim(topic, "/"))
+	for {
+		for name, weight := range s.topicWeights {
+			if normalizeTopicPattern(name) == topic {
+				return weight
+			}
+		}
+		i := strings.LastIndex(topic, "/")
+		if i < 0 {
+			return 1
+		}
+		topic = topic[:i]
+	}
+}
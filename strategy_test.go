@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWeightedStrategy_weight(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	strategy := weightedStrategy{
+		topicWeights: map[string]float64{"kubernetes": 3, "vim": 0, "kubernetes/networking": 2},
+		ageHalfLife:  30 * 24 * time.Hour,
+	}
+
+	tests := []struct {
+		name     string
+		tip      Tip
+		expected float64
+	}{
+		{name: "unweighted topic", tip: Tip{Topic: "git", CreatedAt: now}, expected: 1},
+		{name: "weighted topic", tip: Tip{Topic: "kubernetes", CreatedAt: now}, expected: 3},
+		{name: "inherited weight", tip: Tip{Topic: "Kubernetes/Storage", CreatedAt: now}, expected: 3},
+		{name: "nearest weighted ancestor", tip: Tip{Topic: "kubernetes/networking/dns", CreatedAt: now}, expected: 2},
+		{name: "disabled topic", tip: Tip{Topic: "vim", CreatedAt: now}, expected: 0},
+		{name: "one half-life old", tip: Tip{Topic: "git", CreatedAt: now.AddDate(0, 0, -30)}, expected: 0.5},
+		{name: "rated up", tip: Tip{Topic: "git", CreatedAt: now, Rating: 2}, expected: 4},
+		{name: "rated down", tip: Tip{Topic: "kubernetes", CreatedAt: now, Rating: -1}, expected: 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if weight := strategy.weight(&tt.tip, now); math.Abs(weight-tt.expected) > 1e-9 {
+				t.Errorf("Expected weight %v, got %v", tt.expected, weight)
+			}
+		})
+	}
+}
+
+func TestWeightedStrategy_pick(t *testing.T) {
+	td := &TipsData{Tips: []Tip{
+		{ID: "1", Topic: "kubernetes", CreatedAt: time.Now()},
+		{ID: "2", Topic: "vim", CreatedAt: time.Now()},
+		{ID: "3", Topic: "vim", CreatedAt: time.Now()},
+	}}
+
+	strategy := weightedStrategy{topicWeights: map[string]float64{"kubernetes": 0}}
+	for i := 0; i < 50; i++ {
+		tip, err := strategy.pick(td, tipFilter{})
+		if err != nil {
+			t.Fatalf("pick failed: %v", err)
+		}
+		if tip == nil || tip.Topic != "vim" {
+			t.Fatalf("Expected only vim tips, got %+v", tip)
+		}
+	}
+
+	if tip, _ := strategy.pick(td, tipFilter{topics: []string{"kubernetes"}}); tip != nil {
+		t.Errorf("Expected no tip when every match weighs zero, got %+v", tip)
+	}
+}
+
+func TestNewSelectionStrategy(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	originalShuffle := shuffleFlag
+	defer func() { shuffleFlag = originalShuffle }()
+
+	tests := []struct {
+		name        string
+		flag        string
+		shuffle     bool
+		env         string
+		config      string
+		expected    string
+		expectError bool
+	}{
+		{name: "default", expected: "main.srsStrategy"},
+		{name: "config", config: "random", expected: "main.randomStrategy"},
+		{name: "env over config", env: "weighted", config: "random", expected: "main.weightedStrategy"},
+		{name: "shuffle flag", shuffle: true, env: "random", expected: "*main.shuffleState"},
+		{name: "strategy flag", flag: "random", shuffle: true, expected: "main.randomStrategy"},
+		{name: "unknown", flag: "fifo", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategyFlag, shuffleFlag = tt.flag, tt.shuffle
+			t.Setenv("TIPS_STRATEGY", tt.env)
+			if err := setConfigValue("strategy", tt.config); err != nil {
+				t.Fatalf("setConfigValue failed: %v", err)
+			}
+
+			strategy, err := newSelectionStrategy()
+			if tt.expectError {
+				if err == nil {
+					t.Error("Expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("newSelectionStrategy failed: %v", err)
+			}
+
+			if name := fmt.Sprintf("%T", strategy); name != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Add or remove tags on a tip",
+	Long: `Add or remove tags on a tip.
+
+Tags are lowercase keywords, with spaces turned into dashes. Generated tips
+are tagged by the model; filter tips by tag with --tag and --not-tag.`,
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add <id> <tag>...",
+	Short: "Add tags to a tip",
+	Args:  cobra.MinimumNArgs(2),
+	Run:   func(cmd *cobra.Command, args []string) { retagTip(args[0], args[1:], true) },
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <id> <tag>...",
+	Short: "Remove tags from a tip",
+	Args:  cobra.MinimumNArgs(2),
+	Run:   func(cmd *cobra.Command, args []string) { retagTip(args[0], args[1:], false) },
+}
+
+// withTags returns the tip's tags with tags added or removed, and whether
+// anything changed.
+func (tip *Tip) withTags(tags []string, add bool) ([]string, bool) {
+	updated := slices.Clone(tip.Tags)
+	for _, tag := range normalizeTags(tags) {
+		i := slices.Index(updated, tag)
+		switch {
+		case add && i == -1:
+			updated = append(updated, tag)
+		case !add && i != -1:
+			updated = slices.Delete(updated, i, i+1)
+		}
+	}
+	return updated, !slices.Equal(updated, tip.Tags)
+}
+
+func retagTip(id string, tags []string, add bool) {
+	if len(normalizeTags(tags)) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: Please specify at least one tag\n")
+		os.Exit(1)
+	}
+
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	tip, err := tipsData.resolveTip(id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	updated, changed := tip.withTags(tags, add)
+	if changed {
+		tip.Tags = updated
+		if err := store.Update(*tip); err != nil {
+			fmt.Fprintf(os.Stderr, "Error saving tips: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if len(tip.Tags) == 0 {
+		fmt.Printf("Tip %s has no tags\n", tip.ID)
+		return
+	}
+	fmt.Printf("Tip %s tags: %s\n", tip.ID, strings.Join(tip.Tags, ", "))
+}
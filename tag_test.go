@@ -0,0 +1,59 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTip_withTags(t *testing.T) {
+	tip := &Tip{Tags: []string{"stash", "branch"}}
+
+	tests := []struct {
+		name            string
+		tags            []string
+		add             bool
+		expected        []string
+		expectedChanged bool
+	}{
+		{name: "add new tag", tags: []string{"Rebase"}, add: true, expected: []string{"stash", "branch", "rebase"}, expectedChanged: true},
+		{name: "add existing tag", tags: []string{"stash"}, add: true, expected: []string{"stash", "branch"}},
+		{name: "remove tag", tags: []string{"stash"}, expected: []string{"branch"}, expectedChanged: true},
+		{name: "remove missing tag", tags: []string{"rebase"}, expected: []string{"stash", "branch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, changed := tip.withTags(tt.tags, tt.add)
+			if !reflect.DeepEqual(updated, tt.expected) || changed != tt.expectedChanged {
+				t.Errorf("Expected %v (changed %v), got %v (changed %v)", tt.expected, tt.expectedChanged, updated, changed)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(tip.Tags, []string{"stash", "branch"}) {
+		t.Errorf("withTags should not modify the tip, got %v", tip.Tags)
+	}
+}
+
+func TestRetagTip(t *testing.T) {
+	store := useMemoryStore(t, Tip{ID: "1", Topic: "git", Content: "git stash pop"})
+
+	output := captureStdout(t, func() { retagTip("1", []string{"stash", "Working Tree"}, true) })
+	if !strings.Contains(output, "Tip 1 tags: stash, working-tree") {
+		t.Errorf("Expected the new tags, got '%s'", output)
+	}
+
+	output = captureStdout(t, func() { retagTip("1", []string{"stash"}, false) })
+	if !strings.Contains(output, "Tip 1 tags: working-tree") {
+		t.Errorf("Expected the remaining tag, got '%s'", output)
+	}
+
+	tipsData, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	if tip := tipsData.findTip("1"); tip == nil || !reflect.DeepEqual(tip.Tags, []string{"working-tree"}) {
+		t.Errorf("Expected stored tags [working-tree], got %+v", tip)
+	}
+}
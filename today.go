@@ -0,0 +1,103 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+)
+
+const dateFormat = "2006-01-02"
+
+var dateFlag string
+
+var todayCmd = &cobra.Command{
+	Use:   "today",
+	Short: "Show the tip of the day",
+	Long: `Show the tip of the day.
+
+Everyone using the same tips file sees the same tip on the same day, which
+makes it suitable for a dashboard or shell MOTD. The choice depends only on
+the date (in UTC), the salt and the tips matching --topic, --tag and
+--not-tag. Set a team-wide salt with TIPS_SALT or 'tips config set salt
+<value>' to get a different sequence from other teams.`,
+	Args: cobra.NoArgs,
+	Run:  showTipOfTheDay,
+}
+
+func getTipSalt() (string, error) {
+	if salt := os.Getenv("TIPS_SALT"); salt != "" {
+		return salt, nil
+	}
+
+	config, err := loadConfig()
+	if err != nil {
+		return "", err
+	}
+	return config.Salt, nil
+}
+
+// tipOfTheDay picks the selectable tip whose ID hashes highest together with
+// the date and salt. Adding or removing a tip only changes the pick if it
+// was, or beats, the current one.
+func (td *TipsData) tipOfTheDay(filter tipFilter, date, salt string) *Tip {
+	selectable := newTipFilter(filter)
+
+	var best *Tip
+	var bestScore uint64
+	for i := range td.Tips {
+		tip := &td.Tips[i]
+		if !selectable(tip) {
+			continue
+		}
+
+		sum := sha256.Sum256([]byte(salt + "\x00" + date + "\x00" + tip.ID))
+		score := binary.BigEndian.Uint64(sum[:8])
+		if best == nil || score > bestScore || (score == bestScore && tip.ID < best.ID) {
+			best, bestScore = tip, score
+		}
+	}
+	return best
+}
+
+func showTipOfTheDay(cmd *cobra.Command, args []string) {
+	date := time.Now().UTC().Format(dateFormat)
+	if dateFlag != "" {
+		parsed, err := time.Parse(dateFormat, dateFlag)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid date %q, expected YYYY-MM-DD\n", dateFlag)
+			os.Exit(1)
+		}
+		date = parsed.Format(dateFormat)
+	}
+
+	salt, err := getTipSalt()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	filter := flagFilter()
+	tipsData, err := loadTipsByTopic(filter.topics)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	tip := tipsData.tipOfTheDay(filter, date, salt)
+	if tip == nil {
+		if !filter.isEmpty() {
+			fmt.Printf("No tips found for %s\n", filter)
+		} else {
+			fmt.Println("No tips found. Generate some tips first using: tips generate -t <topic>")
+		}
+		return
+	}
+
+	topicStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("cyan")).Bold(true)
+	fmt.Printf("%s %s\n", topicStyle.Render(fmt.Sprintf("[%s]", tip.Topic)), tip.Content)
+}
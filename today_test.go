@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestTipsData_tipOfTheDay(t *testing.T) {
+	var tips []Tip
+	for i := 0; i < 20; i++ {
+		topic := "git"
+		if i%2 == 1 {
+			topic = "vim"
+		}
+		tips = append(tips, Tip{ID: fmt.Sprintf("tip-%02d", i), Topic: topic})
+	}
+	td := &TipsData{Tips: tips}
+
+	tip := td.tipOfTheDay(tipFilter{}, "2024-05-01", "team")
+	if tip == nil {
+		t.Fatal("Expected a tip of the day")
+	}
+
+	reversed := &TipsData{Tips: slices.Clone(tips)}
+	slices.Reverse(reversed.Tips)
+	if other := reversed.tipOfTheDay(tipFilter{}, "2024-05-01", "team"); other.ID != tip.ID {
+		t.Errorf("Expected the same tip regardless of order, got %s and %s", tip.ID, other.ID)
+	}
+
+	if vimTip := td.tipOfTheDay(tipFilter{topics: []string{"vim"}}, "2024-05-01", "team"); vimTip == nil || vimTip.Topic != "vim" {
+		t.Errorf("Expected a vim tip, got %+v", vimTip)
+	}
+
+	knownAt := time.Now()
+	withKnown := &TipsData{Tips: slices.Clone(tips)}
+	withKnown.findTip(tip.ID).KnownAt = &knownAt
+	if other := withKnown.tipOfTheDay(tipFilter{}, "2024-05-01", "team"); other == nil || other.ID == tip.ID {
+		t.Errorf("Expected known tips to be skipped, got %+v", other)
+	}
+
+	days, salts := make(map[string]struct{}), make(map[string]struct{})
+	for day := 1; day <= 10; day++ {
+		days[td.tipOfTheDay(tipFilter{}, fmt.Sprintf("2024-05-%02d", day), "team").ID] = struct{}{}
+		salts[td.tipOfTheDay(tipFilter{}, "2024-05-01", fmt.Sprintf("team-%d", day)).ID] = struct{}{}
+	}
+	if len(days) == 1 || len(salts) == 1 {
+		t.Errorf("Expected the tip to vary with date and salt, got %d and %d distinct tips", len(days), len(salts))
+	}
+
+	if tip := (&TipsData{}).tipOfTheDay(tipFilter{}, "2024-05-01", ""); tip != nil {
+		t.Errorf("Expected no tip for empty data, got %+v", tip)
+	}
+}
+
+func TestShowTipOfTheDay(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	originalTopics, originalDate := topicFlag, dateFlag
+	defer func() { topicFlag, dateFlag = originalTopics, originalDate }()
+	topicFlag = []string{}
+
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "git stash pop"},
+		Tip{ID: "2", Topic: "vim", Content: "dd deletes a line"},
+	)
+
+	dateFlag = "2024-05-01"
+	first := captureStdout(t, func() { showTipOfTheDay(&cobra.Command{}, nil) })
+	second := captureStdout(t, func() { showTipOfTheDay(&cobra.Command{}, nil) })
+	if first != second || (!strings.Contains(first, "git stash pop") && !strings.Contains(first, "dd deletes a line")) {
+		t.Errorf("Expected the same tip twice, got '%s' and '%s'", first, second)
+	}
+}
+
+func TestSeededSelection(t *testing.T) {
+	originalSeed, originalSeedSet := seedFlag, seedSet
+	defer func() { seedFlag, seedSet = originalSeed, originalSeedSet }()
+	seedFlag, seedSet = 42, true
+
+	var tips []Tip
+	for i := 0; i < 20; i++ {
+		tips = append(tips, Tip{ID: fmt.Sprintf("%d", i), Topic: "git"})
+	}
+	td := &TipsData{Tips: tips}
+
+	sequence := func() []string {
+		strategy := srsStrategy{rng: newTipRand()}
+		var ids []string
+		for i := 0; i < 10; i++ {
+			tip, err := strategy.pick(td, tipFilter{})
+			if err != nil {
+				t.Fatalf("pick failed: %v", err)
+			}
+			ids = append(ids, tip.ID)
+		}
+		return ids
+	}
+
+	if first, second := sequence(), sequence(); !slices.Equal(first, second) {
+		t.Errorf("Expected the same sequence for the same seed, got %v and %v", first, second)
+	}
+}
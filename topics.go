@@ -0,0 +1,433 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	topicsTreeFlag bool
+	topicsIntoFlag string
+)
+
+// Topics are paths like "go/testing". A pattern matches a topic if it
+// matches the topic or one of its ancestors, ignoring case, so "go" and
+// "go/*" both match "go/testing/table". Patterns may use the wildcards of
+// path.Match, where "*" doesn't cross a "/".
+func normalizeTopicPattern(pattern string) string {
+	return strings.Trim(strings.ToLower(strings.TrimSpace(pattern)), "/")
+}
+
+// normalizeTopic tidies a topic for storage, trimming spaces around it and
+// its path segments. Topics can't have empty segments or wildcards, which
+// would make them unmatchable as patterns.
+func normalizeTopic(topic string) (string, error) {
+	segments := strings.Split(strings.Trim(strings.TrimSpace(topic), "/"), "/")
+	for i, segment := range segments {
+		segments[i] = strings.TrimSpace(segment)
+		if segments[i] == "" {
+			if len(segments) == 1 {
+				return "", fmt.Errorf("topic is empty")
+			}
+			return "", fmt.Errorf("topic %q has an empty path segment", topic)
+		}
+	}
+
+	topic = strings.Join(segments, "/")
+	if isTopicGlob(topic) {
+		return "", fmt.Errorf("topic %q can't contain any of * ? [ \\", topic)
+	}
+	return topic, nil
+}
+
+// topicRules are the optional normalisations applied to topics as tips are
+// written: topic_aliases renames a topic and its subtopics, so golang=go
+// stores golang/testing as go/testing, and fold_topic_case lower-cases
+// topics.
+type topicRules struct {
+	aliases  map[string]string
+	foldCase bool
+}
+
+// activeTopicRules is set from the config by applyConfig.
+var activeTopicRules topicRules
+
+func newTopicRules(aliases map[string]string, foldCase bool) topicRules {
+	rules := topicRules{aliases: make(map[string]string, len(aliases)), foldCase: foldCase}
+	for alias, topic := range aliases {
+		rules.aliases[normalizeTopicPattern(alias)] = topic
+	}
+	return rules
+}
+
+// apply normalizes topic and applies the rules to it. Only the alias of
+// the longest matching ancestor is applied, and aliases aren't chained.
+func (r topicRules) apply(topic string) (string, error) {
+	topic, err := normalizeTopic(topic)
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(topic); i > 0; i-- {
+		if i < len(topic) && topic[i] != '/' {
+			continue
+		}
+		if alias, ok := r.aliases[strings.ToLower(topic[:i])]; ok {
+			aliased, err := normalizeTopic(alias + topic[i:])
+			if err != nil {
+				return "", fmt.Errorf("invalid alias for %s: %w", topic[:i], err)
+			}
+			topic = aliased
+			break
+		}
+	}
+
+	if r.foldCase {
+		topic = strings.ToLower(topic)
+	}
+	return topic, nil
+}
+
+// canonicalTopic is the topic a tip given topic is stored under.
+func canonicalTopic(topic string) (string, error) {
+	return activeTopicRules.apply(topic)
+}
+
+// topicSuffix returns what follows parent in topic, if topic is parent or
+// one of its subtopics.
+func topicSuffix(topic, parent string) (string, bool) {
+	if len(topic) < len(parent) || !strings.EqualFold(topic[:len(parent)], parent) {
+		return "", false
+	}
+	if suffix := topic[len(parent):]; suffix == "" || suffix[0] == '/' {
+		return suffix, true
+	}
+	return "", false
+}
+
+func topicMatches(pattern, topic string) bool {
+	topic = strings.ToLower(topic)
+	for i := 0; i <= len(topic); i++ {
+		if i < len(topic) && topic[i] != '/' {
+			continue
+		}
+		if matched, _ := path.Match(pattern, topic[:i]); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAnyTopic(patterns []string, topic string) bool {
+	for _, pattern := range patterns {
+		if topicMatches(pattern, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+func isTopicGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+func validateTopicPatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(normalizeTopicPattern(pattern), ""); err != nil {
+			return fmt.Errorf("invalid topic pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+var topicsCmd = &cobra.Command{
+	Use:   "topics",
+	Short: "List, rename and merge topics",
+	Long: `List topics with the number of tips in each and when a tip was last added.
+
+Topics can be organised as paths like go/testing or k8s/networking. Filtering
+by a parent topic (-t go) includes its children, matching ignores case, and
+globs such as -t 'go/*' are supported. Use --tree to print the hierarchy, with
+each topic's count including its children.
+
+Set topic_aliases (e.g. golang=go) or fold_topic_case with 'tips config set'
+to normalise topics as tips are added, and use rename and merge to tidy up the
+topics already stored.`,
+	Args: cobra.NoArgs,
+	Run:  listTopics,
+}
+
+var topicsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a topic and its subtopics",
+	Long: `Rename a topic, ignoring case, along with its subtopics: renaming go to
+golang moves go/testing to golang/testing. Run 'tips undo' to revert it.`,
+	Args: cobra.ExactArgs(2),
+	Run:  renameTopic,
+}
+
+var topicsMergeCmd = &cobra.Command{
+	Use:   "merge <topic>... --into <topic>",
+	Short: "Merge topics and their subtopics into one",
+	Long: `Move the tips of each topic, ignoring case, into another, keeping their
+subtopics: merging Go and golang into go moves golang/testing to go/testing.
+Run 'tips undo' to revert it.`,
+	Args: cobra.MinimumNArgs(1),
+	Run:  mergeTopics,
+}
+
+// A topicNode counts the tips in a topic and everything below it.
+type topicNode struct {
+	name     string
+	count    int
+	children map[string]*topicNode
+}
+
+func newTopicTree(tips []Tip) *topicNode {
+	root := &topicNode{children: make(map[string]*topicNode)}
+	for _, tip := range tips {
+		node := root
+		node.count++
+		for _, segment := range strings.Split(strings.Trim(tip.Topic, "/"), "/") {
+			key := strings.ToLower(segment)
+			child, ok := node.children[key]
+			if !ok {
+				child = &topicNode{name: segment, children: make(map[string]*topicNode)}
+				node.children[key] = child
+			}
+			child.count++
+			node = child
+		}
+	}
+	return root
+}
+
+func (n *topicNode) sortedChildren() []*topicNode {
+	children := make([]*topicNode, 0, len(n.children))
+	for _, child := range n.children {
+		children = append(children, child)
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return strings.ToLower(children[i].name) < strings.ToLower(children[j].name)
+	})
+	return children
+}
+
+func (n *topicNode) print(prefix string, top bool) {
+	children := n.sortedChildren()
+	for i, child := range children {
+		branch, indent := "├── ", "│   "
+		if i == len(children)-1 {
+			branch, indent = "└── ", "    "
+		}
+		if top {
+			branch, indent = "", ""
+		}
+
+		fmt.Printf("%s%s%s (%d)\n", prefix, branch, child.name, child.count)
+		child.print(prefix+indent, false)
+	}
+}
+
+type topicSummary struct {
+	name      string
+	count     int
+	lastAdded time.Time
+}
+
+// summarizeTopics groups topics case-insensitively, keeping the first
+// spelling seen.
+func summarizeTopics(tips []Tip) []*topicSummary {
+	var summaries []*topicSummary
+	byKey := make(map[string]*topicSummary)
+	for _, tip := range tips {
+		key := strings.ToLower(tip.Topic)
+		summary, ok := byKey[key]
+		if !ok {
+			summary = &topicSummary{name: tip.Topic}
+			byKey[key] = summary
+			summaries = append(summaries, summary)
+		}
+		summary.count++
+		if tip.CreatedAt.After(summary.lastAdded) {
+			summary.lastAdded = tip.CreatedAt
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		return strings.ToLower(summaries[i].name) < strings.ToLower(summaries[j].name)
+	})
+	return summaries
+}
+
+// moveTopics moves the tips in each of the from topics and their subtopics
+// under to, applying the topic rules to the result. Where from topics
+// overlap, the most specific one decides where a tip goes. It returns the
+// tips that moved.
+func (td *TipsData) moveTopics(from []string, to string) ([]Tip, error) {
+	to, err := normalizeTopic(to)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make([]string, len(from))
+	for i, topic := range from {
+		if sources[i], err = normalizeTopic(topic); err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(sources, func(i, j int) bool { return len(sources[i]) > len(sources[j]) })
+
+	found := make(map[string]bool)
+	var moved []Tip
+	for i := range td.Tips {
+		tip := &td.Tips[i]
+		for _, source := range sources {
+			suffix, ok := topicSuffix(tip.Topic, source)
+			if !ok {
+				continue
+			}
+			found[source] = true
+
+			topic, err := canonicalTopic(to + suffix)
+			if err != nil {
+				return nil, err
+			}
+			if topic != tip.Topic {
+				tip.Topic = topic
+				moved = append(moved, *tip)
+			}
+			break
+		}
+	}
+
+	for _, source := range sources {
+		if !found[source] {
+			return nil, fmt.Errorf("no tips found in topic %s", source)
+		}
+	}
+	td.tipKeys = nil
+	return moved, nil
+}
+
+// countDuplicates counts the given tips that have the same topic and content
+// as another tip.
+func (td *TipsData) countDuplicates(tips []Tip) int {
+	keys := make(map[string]int, len(td.Tips))
+	for _, tip := range td.Tips {
+		keys[tipKey(tip.Topic, tip.Content)]++
+	}
+
+	count := 0
+	for _, tip := range tips {
+		if keys[tipKey(tip.Topic, tip.Content)] > 1 {
+			count++
+		}
+	}
+	return count
+}
+
+func listTopics(cmd *cobra.Command, args []string) {
+	tipsData, err := loadTips()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	matches := commandFilter(cmd).matcher()
+	var tips []Tip
+	for _, tip := range tipsData.Tips {
+		if matches(&tip) {
+			tips = append(tips, tip)
+		}
+	}
+
+	if len(tips) == 0 {
+		fmt.Println("No topics found")
+		return
+	}
+
+	if topicsTreeFlag {
+		newTopicTree(tips).print("", true)
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOPIC\tTIPS\tLAST ADDED")
+	for _, summary := range summarizeTopics(tips) {
+		lastAdded := "-"
+		if !summary.lastAdded.IsZero() {
+			lastAdded = summary.lastAdded.Local().Format(dateFormat)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", summary.name, summary.count, lastAdded)
+	}
+	w.Flush()
+}
+
+func renameTopic(cmd *cobra.Command, args []string) {
+	moved, to := moveTopicTips(args[:1], args[1])
+	if len(moved) == 0 {
+		fmt.Printf("Topic %s is already named %s\n", args[0], to)
+		return
+	}
+	fmt.Printf("Renamed %s to %s (%d tips)\n", args[0], to, len(moved))
+}
+
+func mergeTopics(cmd *cobra.Command, args []string) {
+	if strings.TrimSpace(topicsIntoFlag) == "" {
+		fmt.Fprintf(os.Stderr, "Error: Please specify the topic to merge into using --into\n")
+		os.Exit(1)
+	}
+
+	moved, to := moveTopicTips(args, topicsIntoFlag)
+	if len(moved) == 0 {
+		fmt.Printf("All tips are already in %s\n", to)
+		return
+	}
+	fmt.Printf("Merged %s into %s (%d tips)\n", strings.Join(args, ", "), to, len(moved))
+}
+
+// moveTopicTips moves tips for rename and merge, returning the moved tips
+// and the topic they moved to.
+func moveTopicTips(from []string, to string) ([]Tip, string) {
+	store, err := openStore()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening tips store: %v\n", err)
+		os.Exit(1)
+	}
+	defer store.Close()
+
+	tipsData, err := store.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error loading tips: %v\n", err)
+		os.Exit(1)
+	}
+
+	moved, err := tipsData.moveTopics(from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if to, err = canonicalTopic(to); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if len(moved) == 0 {
+		return nil, to
+	}
+
+	if err := store.Update(moved...); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving tips: %v\n", err)
+		os.Exit(1)
+	}
+	if n := tipsData.countDuplicates(moved); n > 0 {
+		fmt.Printf("Note: %d moved tips duplicate other tips; find them with 'tips list -t %s --sort topic'\n", n, to)
+	}
+	return moved, to
+}
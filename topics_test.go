@@ -0,0 +1,202 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func TestTopicMatches(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		topic    string
+		expected bool
+	}{
+		{pattern: "go", topic: "go", expected: true},
+		{pattern: "go", topic: "Go/Testing", expected: true},
+		{pattern: "go", topic: "golang", expected: false},
+		{pattern: "go/testing", topic: "go", expected: false},
+		{pattern: "go/*", topic: "go", expected: false},
+		{pattern: "go/*", topic: "go/testing/table", expected: true},
+		{pattern: "*/networking", topic: "k8s/networking", expected: true},
+		{pattern: "k8s/net*", topic: "k8s/storage", expected: false},
+		{pattern: "go*", topic: "golang", expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+"~"+tt.topic, func(t *testing.T) {
+			if got := topicMatches(normalizeTopicPattern(tt.pattern), tt.topic); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestValidateTopicPatterns(t *testing.T) {
+	if err := validateTopicPatterns([]string{"go/*", "K8s/"}); err != nil {
+		t.Errorf("Expected valid patterns, got %v", err)
+	}
+	if err := validateTopicPatterns([]string{"go/[testing"}); err == nil {
+		t.Error("Expected error for malformed pattern")
+	}
+}
+
+func TestListTopics(t *testing.T) {
+	originalTopics, originalTree := topicFlag, topicsTreeFlag
+	defer func() { topicFlag, topicsTreeFlag = originalTopics, originalTree }()
+	topicFlag, topicsTreeFlag = []string{}, false
+
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "go/testing", Content: "use t.Run for subtests", CreatedAt: time.Date(2024, 5, 20, 12, 0, 0, 0, time.Local)},
+		Tip{ID: "2", Topic: "Go/Testing", Content: "use t.Helper in helpers", CreatedAt: time.Date(2024, 1, 2, 12, 0, 0, 0, time.Local)},
+		Tip{ID: "3", Topic: "go/concurrency", Content: "close channels from the sender", CreatedAt: time.Date(2024, 3, 1, 12, 0, 0, 0, time.Local)},
+		Tip{ID: "4", Topic: "vim", Content: "dd deletes a line"},
+	)
+
+	output := captureStdout(t, func() { listTopics(&cobra.Command{}, nil) })
+	expected := "TOPIC           TIPS  LAST ADDED\ngo/concurrency  1     2024-03-01\ngo/testing      2     2024-05-20\nvim             1     -\n"
+	if output != expected {
+		t.Errorf("Expected flat listing:\n%s\ngot:\n%s", expected, output)
+	}
+
+	topicsTreeFlag = true
+	output = captureStdout(t, func() { listTopics(&cobra.Command{}, nil) })
+	expected = "go (3)\n├── concurrency (1)\n└── testing (2)\nvim (1)\n"
+	if output != expected {
+		t.Errorf("Expected tree:\n%s\ngot:\n%s", expected, output)
+	}
+
+	// A default topic from the config file doesn't narrow the listing.
+	topicFlag = []string{"vim"}
+	output = captureStdout(t, func() { listTopics(&cobra.Command{}, nil) })
+	if output != expected {
+		t.Errorf("Expected default topics to be ignored:\n%s\ngot:\n%s", expected, output)
+	}
+
+	cmd := topicCommand(t, "GO")
+	output = captureStdout(t, func() { listTopics(cmd, nil) })
+	expected = "go (3)\n├── concurrency (1)\n└── testing (2)\n"
+	if output != expected {
+		t.Errorf("Expected filtered tree:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestTopicRules_apply(t *testing.T) {
+	rules := newTopicRules(map[string]string{"Golang": "go", "k8s": "kubernetes", "go/tests": "go/testing"}, false)
+	folding := newTopicRules(map[string]string{"golang": "Go"}, true)
+
+	tests := []struct {
+		name        string
+		rules       topicRules
+		topic       string
+		expected    string
+		expectError bool
+	}{
+		{name: "trimmed", rules: rules, topic: " vim / motions ", expected: "vim/motions"},
+		{name: "alias", rules: rules, topic: "golang", expected: "go"},
+		{name: "alias ignores case", rules: rules, topic: "GOLANG/Testing", expected: "go/Testing"},
+		{name: "longest alias wins", rules: rules, topic: "go/tests/table", expected: "go/testing/table"},
+		{name: "aliases aren't chained", rules: newTopicRules(map[string]string{"a": "b", "b": "c"}, false), topic: "a", expected: "b"},
+		{name: "partial segment", rules: rules, topic: "k8sx", expected: "k8sx"},
+		{name: "case folded", rules: folding, topic: "Golang/Testing", expected: "go/testing"},
+		{name: "invalid", rules: rules, topic: "go//testing", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topic, err := tt.rules.apply(tt.topic)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got %q", topic)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("apply failed: %v", err)
+			}
+			if topic != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, topic)
+			}
+		})
+	}
+}
+
+func TestTipsData_moveTopics(t *testing.T) {
+	newTips := func() *TipsData {
+		return &TipsData{Tips: []Tip{
+			{ID: "1", Topic: "Go", Content: "go vet finds bugs"},
+			{ID: "2", Topic: "golang/testing", Content: "use t.Run for subtests"},
+			{ID: "3", Topic: "golang", Content: "go fmt formats code"},
+			{ID: "4", Topic: "go/testing", Content: "use t.Helper in helpers"},
+			{ID: "5", Topic: "golangci", Content: "golangci-lint runs linters"},
+		}}
+	}
+	topics := func(td *TipsData) string {
+		var topics []string
+		for _, tip := range td.Tips {
+			topics = append(topics, tip.Topic)
+		}
+		return strings.Join(topics, ",")
+	}
+
+	tests := []struct {
+		name        string
+		from        []string
+		to          string
+		expected    string
+		moved       int
+		expectError bool
+	}{
+		{name: "rename with subtopics", from: []string{"golang"}, to: "go", expected: "Go,go/testing,go,go/testing,golangci", moved: 2},
+		{name: "rename case", from: []string{"go"}, to: "go", expected: "go,golang/testing,golang,go/testing,golangci", moved: 1},
+		{name: "merge", from: []string{"Go", "golang"}, to: "lang/go", expected: "lang/go,lang/go/testing,lang/go,lang/go/testing,golangci", moved: 4},
+		{name: "most specific source wins", from: []string{"golang", "golang/testing"}, to: "go", expected: "Go,go,go,go/testing,golangci", moved: 2},
+		{name: "missing topic", from: []string{"rust"}, to: "go", expectError: true},
+		{name: "invalid topic", from: []string{"golang"}, to: "go/*", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			td := newTips()
+			moved, err := td.moveTopics(tt.from, tt.to)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got %s", topics(td))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("moveTopics failed: %v", err)
+			}
+			if got := topics(td); got != tt.expected || len(moved) != tt.moved {
+				t.Errorf("Expected %s with %d moved, got %s with %d moved", tt.expected, tt.moved, got, len(moved))
+			}
+		})
+	}
+}
+
+func TestMergeTopics(t *testing.T) {
+	originalInto := topicsIntoFlag
+	defer func() { topicsIntoFlag = originalInto }()
+	topicsIntoFlag = "go"
+
+	store := useMemoryStore(t,
+		Tip{ID: "1", Topic: "Go", Content: "go vet finds bugs"},
+		Tip{ID: "2", Topic: "golang", Content: "Go vet finds bugs"},
+		Tip{ID: "3", Topic: "golang/testing", Content: "use t.Run for subtests"},
+		Tip{ID: "4", Topic: "vim", Content: "dd deletes a line"},
+	)
+
+	output := captureStdout(t, func() { mergeTopics(&cobra.Command{}, []string{"Go", "golang"}) })
+	if !strings.Contains(output, "Merged Go, golang into go (3 tips)") || !strings.Contains(output, "2 moved tips duplicate other tips") {
+		t.Errorf("Expected three tips to be merged with a duplicate noted, got '%s'", output)
+	}
+
+	tips, _ := store.Load()
+	names := summarizeTopics(tips.Tips)
+	if len(names) != 3 || names[0].name != "go" || names[0].count != 2 || names[1].name != "go/testing" {
+		t.Errorf("Expected go, go/testing and vim, got %+v", tips.Tips)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -13,35 +14,70 @@ import (
 type tickMsg time.Time
 
 type model struct {
+	store       TipStore
 	tipsData    *TipsData
 	currentTip  *Tip
-	topicFilter []string
+	filter      tipFilter
 	refreshRate time.Duration
 	lastRefresh time.Time
 	quit        bool
 	showNewTip  bool
 	message     string
+
+	strategy selectionStrategy
+
+	// history holds the IDs of the tips shown this session, oldest first, and
+	// historyPos the one on screen. views, when set, logs every tip shown.
+	history    []string
+	historyPos int
+	views      *viewLog
+
+	// searching is set while a query is typed after '/'. matches holds the
+	// IDs of the results not shown yet, and searchTerms the words to
+	// highlight.
+	searching   bool
+	searchInput string
+	searchQuery string
+	searchTerms []string
+	matches     []string
+	matchTotal  int
+
+	knownThisSession []string
+}
+
+func initialModel(topics []string, refreshMinutes int) (model, error) {
+	strategy, err := newSelectionStrategy()
+	if err != nil {
+		return model{}, err
+	}
+	store, err := openStore()
+	if err != nil {
+		return model{}, fmt.Errorf("failed to open tips store: %w", err)
+	}
+	return newModel(store, strategy, topics, refreshMinutes), nil
 }
 
-func initialModel(topics []string, refreshMinutes int) model {
+func newModel(store TipStore, strategy selectionStrategy, topics []string, refreshMinutes int) model {
 	lipgloss.SetColorProfile(termenv.ANSI256)
 
-	tipsData, err := loadTips()
+	tipsData, err := loadByTopic(store, topics)
 	if err != nil {
 		fmt.Printf("Error loading tips: %v\n", err)
 		tipsData = &TipsData{}
 	}
 
 	m := model{
-		topicFilter: topics,
+		store:       store,
+		filter:      tipFilter{topics: topics, tags: tagFlag, notTags: notTagFlag},
 		refreshRate: time.Duration(refreshMinutes) * time.Minute,
 		showNewTip:  true,
 		tipsData:    tipsData,
+		strategy:    strategy,
 	}
 
 	if tipsData != nil && len(tipsData.Tips) > 0 {
-		if tip := tipsData.getRandomTip(topics); tip != nil {
-			m.currentTip = tip
+		if tip := m.nextTip(); tip != nil {
+			m.showTip(tip)
 			m.showNewTip = false
 		}
 	}
@@ -57,9 +93,9 @@ func tickCmd(d time.Duration) tea.Cmd {
 	return tea.Tick(d, func(t time.Time) tea.Msg { return tickMsg(t) })
 }
 
-func loadTipsCmd() tea.Cmd {
+func loadTipsCmd(store TipStore, topics []string) tea.Cmd {
 	return func() tea.Msg {
-		tipsData, err := loadTips()
+		tipsData, err := loadByTopic(store, topics)
 		if err != nil {
 			return err
 		}
@@ -76,23 +112,55 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		if m.searching {
+			m.updateSearchInput(msg)
+			return m, nil
+		}
+
 		switch msg.String() {
 		case "q":
 			m.quit = true
 			return m, tea.Quit
 		case "n":
-			m.showNewTip = true
+			if len(m.matches) > 0 {
+				m.nextMatch()
+			} else {
+				m.showNewTip = true
+			}
+		case "/":
+			m.searching = true
+			m.searchInput = ""
+			m.message = ""
+		case "esc":
+			m.clearSearch()
+		case "p", "left":
+			m.stepHistory(-1)
+		case "right":
+			m.stepHistory(1)
 		case "k":
 			if m.currentTip != nil && m.tipsData != nil {
-				if m.tipsData.removeTip(m.currentTip.ID) {
-					if err := saveTips(m.tipsData); err != nil {
+				if tip := m.tipsData.findTip(m.currentTip.ID); tip != nil {
+					now := time.Now()
+					tip.KnownAt = &now
+					if err := m.store.Update(*tip); err != nil {
 						m.message = fmt.Sprintf("Error saving: %v", err)
 					} else {
+						m.knownThisSession = append(m.knownThisSession, tip.ID)
 						m.message = "Tip marked as known!"
 						m.showNewTip = true
 					}
 				}
 			}
+		case "u":
+			m.undoKnown()
+		case "1", "2", "3", "4":
+			m.gradeTip(grade(msg.String()[0] - '0'))
+		case "+", "-":
+			delta := 1
+			if msg.String() == "-" {
+				delta = -1
+			}
+			m.rateTip(delta)
 		}
 
 	case *TipsData:
@@ -103,7 +171,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tickMsg:
 		m.showNewTip = true
 		m.lastRefresh = time.Time(msg)
-		return m, tea.Batch(tickCmd(m.refreshRate), loadTipsCmd())
+		return m, tea.Batch(tickCmd(m.refreshRate), loadTipsCmd(m.store, m.filter.topics))
 
 	case error:
 		m.message = fmt.Sprintf("Error: %v", msg)
@@ -111,16 +179,216 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	}
 
 	if m.showNewTip && m.tipsData != nil {
-		if newTip := m.tipsData.getRandomTip(m.topicFilter); newTip != nil {
-			m.currentTip = newTip
+		m.message = ""
+		m.clearSearch()
+		if newTip := m.nextTip(); newTip != nil {
+			m.showTip(newTip)
+		} else if m.currentTip != nil && m.currentTip.isKnown() {
+			m.currentTip = nil
 		}
 		m.showNewTip = false
-		m.message = ""
 	}
 
 	return m, nil
 }
 
+func (m *model) nextTip() *Tip {
+	tip, err := m.strategy.pick(m.tipsData, m.filter)
+	if err != nil {
+		m.message = fmt.Sprintf("Error picking tip: %v", err)
+	}
+	return tip
+}
+
+// showTip puts a newly picked tip on screen. It goes to the end of the
+// history, even when browsing back, so no earlier tip is lost.
+func (m *model) showTip(tip *Tip) {
+	m.currentTip = tip
+	m.history = append(m.history, tip.ID)
+	m.historyPos = len(m.history) - 1
+
+	if m.views != nil {
+		if err := m.views.append(tip.ID); err != nil {
+			m.message = fmt.Sprintf("Error saving: %v", err)
+		}
+	}
+}
+
+// stepHistory moves back (-1) or forward (1) through the tips shown this
+// session, skipping any deleted since. A refresh that is still pending is
+// dropped, so browsing isn't interrupted.
+func (m *model) stepHistory(step int) {
+	if m.tipsData == nil {
+		return
+	}
+	m.showNewTip = false
+
+	for pos := m.historyPos + step; pos >= 0 && pos < len(m.history); pos += step {
+		if tip := m.tipsData.findTip(m.history[pos]); tip != nil {
+			m.currentTip = tip
+			m.historyPos = pos
+			m.message = ""
+			return
+		}
+	}
+
+	if step < 0 {
+		m.message = "No earlier tips"
+	} else {
+		m.message = "No later tips"
+	}
+}
+
+func (m *model) updateSearchInput(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEsc:
+		m.searching = false
+	case tea.KeyEnter:
+		m.searching = false
+		m.search(m.searchInput)
+	case tea.KeyBackspace:
+		if runes := []rune(m.searchInput); len(runes) > 0 {
+			m.searchInput = string(runes[:len(runes)-1])
+		}
+	case tea.KeySpace:
+		m.searchInput += " "
+	case tea.KeyRunes:
+		m.searchInput += string(msg.Runes)
+	}
+}
+
+// search shows the best unknown tip matching query, leaving the rest for 'n'.
+// A refresh that is still pending is dropped, like when browsing history.
+func (m *model) search(query string) {
+	if strings.TrimSpace(query) == "" || m.tipsData == nil {
+		return
+	}
+	m.showNewTip = false
+
+	// The saved index covers every tip, so when only some topics were loaded
+	// they are indexed in memory instead.
+	var index *searchIndex
+	if len(m.filter.topics) > 0 {
+		index = buildSearchIndex("", m.tipsData)
+	} else {
+		var err error
+		if index, err = openSearchIndex(m.tipsData); err != nil {
+			m.message = fmt.Sprintf("Error opening search index: %v", err)
+			index = buildSearchIndex("", m.tipsData)
+		}
+	}
+
+	var matches []string
+	for _, result := range index.search(m.tipsData, query, m.filter) {
+		if !result.tip.isKnown() {
+			matches = append(matches, result.tip.ID)
+		}
+	}
+	if len(matches) == 0 {
+		m.message = fmt.Sprintf("No tips match %q", query)
+		return
+	}
+
+	m.searchQuery = query
+	m.searchTerms = uniqueTerms(query)
+	m.matches = matches
+	m.matchTotal = len(matches)
+	m.nextMatch()
+}
+
+func (m *model) nextMatch() {
+	for len(m.matches) > 0 {
+		tip := m.tipsData.findTip(m.matches[0])
+		m.matches = m.matches[1:]
+		if tip != nil {
+			m.showTip(tip)
+			m.message = fmt.Sprintf("Match %d of %d for %q", m.matchTotal-len(m.matches), m.matchTotal, m.searchQuery)
+			return
+		}
+	}
+	m.message = "No more matches"
+}
+
+func (m *model) clearSearch() {
+	m.searchQuery = ""
+	m.searchTerms = nil
+	m.matches = nil
+	m.matchTotal = 0
+}
+
+// gradeTip reschedules the current tip and moves on to the next one.
+func (m *model) gradeTip(g grade) {
+	if m.currentTip == nil || m.tipsData == nil {
+		return
+	}
+
+	tip := m.tipsData.findTip(m.currentTip.ID)
+	if tip == nil {
+		return
+	}
+
+	tip.review(g, time.Now())
+	if err := m.store.Update(*tip); err != nil {
+		m.message = fmt.Sprintf("Error saving: %v", err)
+		return
+	}
+	m.showNewTip = true
+}
+
+// rateTip nudges the current tip's rating, which the weighted strategy uses
+// to show it more or less often.
+func (m *model) rateTip(delta int) {
+	if m.currentTip == nil || m.tipsData == nil {
+		return
+	}
+
+	tip := m.tipsData.findTip(m.currentTip.ID)
+	if tip == nil {
+		return
+	}
+
+	rating := min(max(tip.Rating+delta, minRating), maxRating)
+	if rating == tip.Rating {
+		m.message = fmt.Sprintf("Rating: %+d", rating)
+		return
+	}
+
+	tip.Rating = rating
+	if err := m.store.Update(*tip); err != nil {
+		m.message = fmt.Sprintf("Error saving: %v", err)
+		return
+	}
+	m.currentTip = tip
+	m.message = fmt.Sprintf("Rating: %+d", rating)
+}
+
+// undoKnown restores the tip most recently marked as known in this session.
+func (m *model) undoKnown() {
+	if len(m.knownThisSession) == 0 || m.tipsData == nil {
+		m.message = "Nothing to undo"
+		return
+	}
+
+	id := m.knownThisSession[len(m.knownThisSession)-1]
+	m.knownThisSession = m.knownThisSession[:len(m.knownThisSession)-1]
+
+	tip := m.tipsData.findTip(id)
+	if tip == nil {
+		m.message = "Tip no longer exists"
+		return
+	}
+
+	tip.KnownAt = nil
+	if err := m.store.Update(*tip); err != nil {
+		m.message = fmt.Sprintf("Error saving: %v", err)
+		return
+	}
+
+	m.currentTip = tip
+	m.showNewTip = false
+	m.message = "Tip restored!"
+}
+
 func (m model) View() string {
 	if m.quit {
 		return ""
@@ -135,8 +403,8 @@ func (m model) View() string {
 	}
 
 	if m.currentTip == nil {
-		if len(m.topicFilter) > 0 {
-			return fmt.Sprintf("No tips found for topics: %v\n\nPress 'q' to quit.", m.topicFilter)
+		if !m.filter.isEmpty() {
+			return fmt.Sprintf("No tips found for %s\n\nPress 'q' to quit.", m.filter)
 		}
 		return "No tips available!\n\nPress 'q' to quit."
 	}
@@ -145,12 +413,23 @@ func (m model) View() string {
 	contentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFFFF"))
 	controlsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#808080")).MarginTop(1)
 	messageStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).MarginTop(1)
+	tagStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#808080"))
+	matchStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FFFF00")).Bold(true)
 
-	output := topicStyle.Render(fmt.Sprintf("[%s]", m.currentTip.Topic)) + " " +
-		contentStyle.Render(m.currentTip.Content) + "\n" +
-		controlsStyle.Render(fmt.Sprintf("n:next | k:known | q:quit | refresh:%dm", int(m.refreshRate.Minutes())))
+	content := contentStyle.Render(m.currentTip.Content)
+	if len(m.searchTerms) > 0 {
+		content = highlightTerms(m.currentTip.Content, m.searchTerms, func(word string) string { return matchStyle.Render(word) })
+	}
+	output := topicStyle.Render(fmt.Sprintf("[%s]", m.currentTip.Topic)) + " " + content
+	if len(m.currentTip.Tags) > 0 {
+		output += " " + tagStyle.Render("#"+strings.Join(m.currentTip.Tags, " #"))
+	}
+	output += "\n" +
+		controlsStyle.Render(fmt.Sprintf("1:again 2:hard 3:good 4:easy | +/-:rate | n:next | p/←:back →:forward | /:search | k:known | u:undo known | q:quit | refresh:%dm", int(m.refreshRate.Minutes())))
 
-	if m.message != "" {
+	if m.searching {
+		output += "\n" + messageStyle.Render("/"+m.searchInput+"█  (enter:search esc:cancel)")
+	} else if m.message != "" {
 		output += "\n" + messageStyle.Render(m.message)
 	}
 
@@ -158,9 +437,24 @@ func (m model) View() string {
 }
 
 func runBubbleTeaShow() error {
-	m := initialModel(topicFlag, refreshFlag)
+	m, err := initialModel(topicFlag, refreshFlag)
+	if err != nil {
+		return err
+	}
+	defer m.store.Close()
+
+	views, err := openViewLog()
+	if err != nil {
+		fmt.Printf("Error opening view log: %v\n", err)
+	} else {
+		m.views = views
+		if m.currentTip != nil {
+			views.append(m.currentTip.ID)
+		}
+	}
+
 	p := tea.NewProgram(m, tea.WithInput(os.Stdin))
-	_, err := p.Run()
+	_, err = p.Run()
 
 	if err != nil {
 		return runSimpleShow()
@@ -170,26 +464,42 @@ func runBubbleTeaShow() error {
 }
 
 func runSimpleShow() error {
-	tipsData, err := loadTips()
+	filter := flagFilter()
+	tipsData, err := loadTipsByTopic(filter.topics)
 	if err != nil {
 		return fmt.Errorf("error loading tips: %w", err)
 	}
 
-	if len(tipsData.Tips) == 0 {
+	if len(tipsData.Tips) == 0 && filter.isEmpty() {
 		fmt.Println("No tips found. Generate some tips first using: tips generate -t <topic>")
 		return nil
 	}
 
-	tip := tipsData.getRandomTip(topicFlag)
+	strategy, err := newSelectionStrategy()
+	if err != nil {
+		return err
+	}
+	tip, err := strategy.pick(tipsData, filter)
+	if err != nil {
+		return err
+	}
 	if tip == nil {
-		if len(topicFlag) > 0 {
-			fmt.Printf("No tips found for topics: %v\n", topicFlag)
+		if !filter.isEmpty() {
+			fmt.Printf("No tips found for %s\n", filter)
 		} else {
 			fmt.Println("No tips available!")
 		}
 		return nil
 	}
 
+	views, err := openViewLog()
+	if err != nil {
+		return err
+	}
+	if err := views.append(tip.ID); err != nil {
+		return err
+	}
+
 	topicStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("cyan")).Bold(true)
 	controlsStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("gray"))
 
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -8,18 +9,30 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// testModel builds the show model, failing the test if it can't.
+func testModel(t *testing.T, topics []string, refreshMinutes int) model {
+	t.Helper()
+	m, err := initialModel(topics, refreshMinutes)
+	if err != nil {
+		t.Fatalf("initialModel failed: %v", err)
+	}
+	return m
+}
+
 func TestInitialModel(t *testing.T) {
+	useMemoryStore(t)
+
 	topics := []string{"git", "vim"}
 	refreshMinutes := 30
 
-	m := initialModel(topics, refreshMinutes)
+	m := testModel(t, topics, refreshMinutes)
 
-	if len(m.topicFilter) != 2 {
-		t.Errorf("Expected 2 topic filters, got %d", len(m.topicFilter))
+	if len(m.filter.topics) != 2 {
+		t.Errorf("Expected 2 topic filters, got %d", len(m.filter.topics))
 	}
 
-	if m.topicFilter[0] != "git" || m.topicFilter[1] != "vim" {
-		t.Errorf("Topic filter not set correctly: %v", m.topicFilter)
+	if m.filter.topics[0] != "git" || m.filter.topics[1] != "vim" {
+		t.Errorf("Topic filter not set correctly: %v", m.filter.topics)
 	}
 
 	expectedDuration := time.Duration(refreshMinutes) * time.Minute
@@ -36,8 +49,35 @@ func TestInitialModel(t *testing.T) {
 	}
 }
 
+func TestInitialModel_InvalidStrategy(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	useMemoryStore(t)
+	t.Setenv("TIPS_STRATEGY", "fifo")
+
+	if _, err := initialModel([]string{}, 60); err == nil || !strings.Contains(err.Error(), "fifo") {
+		t.Errorf("Expected an invalid strategy error, got %v", err)
+	}
+}
+
+type failingStrategy struct{}
+
+func (failingStrategy) pick(td *TipsData, filter tipFilter) (*Tip, error) {
+	return nil, fmt.Errorf("shuffle state is corrupt")
+}
+
+func TestNewModel_PickError(t *testing.T) {
+	store := newMemoryStore(Tip{ID: "1", Topic: "git", Content: "git stash pop"})
+	m := newModel(store, failingStrategy{}, []string{}, 60)
+
+	if m.message != "Error picking tip: shuffle state is corrupt" {
+		t.Errorf("Expected a selection error, got %q", m.message)
+	}
+}
+
 func TestModelInit(t *testing.T) {
-	m := initialModel([]string{}, 60)
+	useMemoryStore(t)
+
+	m := testModel(t, []string{}, 60)
 
 	cmd := m.Init()
 	if cmd == nil {
@@ -46,6 +86,8 @@ func TestModelInit(t *testing.T) {
 }
 
 func TestModelUpdate_KeyMessages(t *testing.T) {
+	useMemoryStore(t)
+
 	tests := []struct {
 		name         string
 		key          string
@@ -74,7 +116,7 @@ func TestModelUpdate_KeyMessages(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			m := initialModel([]string{}, 60)
+			m := testModel(t, []string{}, 60)
 			m.tipsData = &TipsData{
 				Tips: []Tip{
 					{ID: "1", Topic: "git", Content: "test tip", CreatedAt: time.Now()},
@@ -101,7 +143,9 @@ func TestModelUpdate_KeyMessages(t *testing.T) {
 }
 
 func TestModelUpdate_CtrlC(t *testing.T) {
-	m := initialModel([]string{}, 60)
+	useMemoryStore(t)
+
+	m := testModel(t, []string{}, 60)
 
 	keyMsg := tea.KeyMsg{Type: tea.KeyCtrlC}
 	updatedModel, cmd := m.Update(keyMsg)
@@ -117,7 +161,9 @@ func TestModelUpdate_CtrlC(t *testing.T) {
 }
 
 func TestModelUpdate_TipsData(t *testing.T) {
-	m := initialModel([]string{}, 60)
+	useMemoryStore(t)
+
+	m := testModel(t, []string{}, 60)
 
 	newTipsData := &TipsData{
 		Tips: []Tip{
@@ -138,7 +184,9 @@ func TestModelUpdate_TipsData(t *testing.T) {
 }
 
 func TestModelUpdate_TickMessage(t *testing.T) {
-	m := initialModel([]string{}, 60)
+	useMemoryStore(t)
+
+	m := testModel(t, []string{}, 60)
 
 	tickMsg := tickMsg(time.Now())
 	updatedModel, cmd := m.Update(tickMsg)
@@ -154,7 +202,9 @@ func TestModelUpdate_TickMessage(t *testing.T) {
 }
 
 func TestModelUpdate_ErrorMessage(t *testing.T) {
-	m := initialModel([]string{}, 60)
+	useMemoryStore(t)
+
+	m := testModel(t, []string{}, 60)
 
 	testError := &TestError{message: "test error"}
 	updatedModel, _ := m.Update(testError)
@@ -166,6 +216,8 @@ func TestModelUpdate_ErrorMessage(t *testing.T) {
 }
 
 func TestModelView(t *testing.T) {
+	useMemoryStore(t)
+
 	tests := []struct {
 		name           string
 		setupModel     func() model
@@ -174,7 +226,7 @@ func TestModelView(t *testing.T) {
 		{
 			name: "quit state",
 			setupModel: func() model {
-				m := initialModel([]string{}, 60)
+				m := testModel(t, []string{}, 60)
 				m.quit = true
 				return m
 			},
@@ -183,7 +235,7 @@ func TestModelView(t *testing.T) {
 		{
 			name: "loading state",
 			setupModel: func() model {
-				m := initialModel([]string{}, 60)
+				m := testModel(t, []string{}, 60)
 				m.tipsData = nil
 				return m
 			},
@@ -192,7 +244,7 @@ func TestModelView(t *testing.T) {
 		{
 			name: "no tips",
 			setupModel: func() model {
-				m := initialModel([]string{}, 60)
+				m := testModel(t, []string{}, 60)
 				m.tipsData = &TipsData{Tips: []Tip{}}
 				return m
 			},
@@ -201,7 +253,7 @@ func TestModelView(t *testing.T) {
 		{
 			name: "no current tip",
 			setupModel: func() model {
-				m := initialModel([]string{}, 60)
+				m := testModel(t, []string{}, 60)
 				m.tipsData = &TipsData{
 					Tips: []Tip{
 						{ID: "1", Topic: "git", Content: "test", CreatedAt: time.Now()},
@@ -215,7 +267,7 @@ func TestModelView(t *testing.T) {
 		{
 			name: "filtered no tips",
 			setupModel: func() model {
-				m := initialModel([]string{"bash"}, 60)
+				m := testModel(t, []string{"bash"}, 60)
 				m.tipsData = &TipsData{
 					Tips: []Tip{
 						{ID: "1", Topic: "git", Content: "test", CreatedAt: time.Now()},
@@ -229,7 +281,7 @@ func TestModelView(t *testing.T) {
 		{
 			name: "normal tip display",
 			setupModel: func() model {
-				m := initialModel([]string{}, 60)
+				m := testModel(t, []string{}, 60)
 				m.tipsData = &TipsData{
 					Tips: []Tip{
 						{ID: "1", Topic: "git", Content: "test tip content", CreatedAt: time.Now()},
@@ -243,7 +295,7 @@ func TestModelView(t *testing.T) {
 		{
 			name: "tip with message",
 			setupModel: func() model {
-				m := initialModel([]string{}, 60)
+				m := testModel(t, []string{}, 60)
 				m.tipsData = &TipsData{
 					Tips: []Tip{
 						{ID: "1", Topic: "git", Content: "test tip", CreatedAt: time.Now()},
@@ -279,7 +331,11 @@ func TestTickCmd(t *testing.T) {
 }
 
 func TestLoadTipsCmd(t *testing.T) {
-	cmd := loadTipsCmd()
+	store := newMemoryStore(
+		Tip{ID: "1", Topic: "git", Content: "test tip", CreatedAt: time.Now()},
+		Tip{ID: "2", Topic: "vim", Content: "another tip", CreatedAt: time.Now()},
+	)
+	cmd := loadTipsCmd(store, []string{"git"})
 
 	if cmd == nil {
 		t.Error("loadTipsCmd should return a command")
@@ -287,9 +343,13 @@ func TestLoadTipsCmd(t *testing.T) {
 
 	msg := cmd()
 
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case *TipsData:
+		if len(msg.Tips) != 1 || msg.Tips[0].ID != "1" {
+			t.Errorf("Expected the git tip from store, got %+v", msg.Tips)
+		}
 	case error:
+		t.Errorf("Unexpected error loading tips: %v", msg)
 	default:
 		t.Errorf("Expected TipsData or error, got %T", msg)
 	}
@@ -306,13 +366,12 @@ func TestRunSimpleShow(t *testing.T) {
 }
 
 func TestModelMarkKnown(t *testing.T) {
-	m := initialModel([]string{}, 60)
-	m.tipsData = &TipsData{
-		Tips: []Tip{
-			{ID: "1", Topic: "git", Content: "test tip 1", CreatedAt: time.Now()},
-			{ID: "2", Topic: "vim", Content: "test tip 2", CreatedAt: time.Now()},
-		},
-	}
+	store := useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "test tip 1", CreatedAt: time.Now()},
+		Tip{ID: "2", Topic: "vim", Content: "test tip 2", CreatedAt: time.Now()},
+	)
+
+	m := testModel(t, []string{}, 60)
 	m.currentTip = &m.tipsData.Tips[0]
 	m.showNewTip = false
 	initialTipCount := len(m.tipsData.Tips)
@@ -321,14 +380,207 @@ func TestModelMarkKnown(t *testing.T) {
 	updatedModel, _ := m.Update(keyMsg)
 	updated := updatedModel.(model)
 
-	if len(updated.tipsData.Tips) != initialTipCount-1 {
-		t.Errorf("Expected %d tips after removal, got %d", initialTipCount-1, len(updated.tipsData.Tips))
+	if len(updated.tipsData.Tips) != initialTipCount {
+		t.Errorf("Expected %d tips after archiving, got %d", initialTipCount, len(updated.tipsData.Tips))
+	}
+
+	if tip := updated.tipsData.findTip("1"); tip == nil || !tip.isKnown() {
+		t.Errorf("Tip with ID '1' should have been marked as known, got %+v", tip)
+	}
+
+	if updated.currentTip == nil || updated.currentTip.ID != "2" {
+		t.Errorf("Expected the next tip to be the unknown tip 2, got %+v", updated.currentTip)
+	}
+
+	stored, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	if len(stored.Tips) != initialTipCount {
+		t.Errorf("Expected %d tips in store after archiving, got %d", initialTipCount, len(stored.Tips))
+	}
+	if tip := stored.findTip("1"); tip == nil || !tip.isKnown() {
+		t.Errorf("Expected stored tip 1 to be known, got %+v", tip)
+	}
+}
+
+func TestModelGradeTip(t *testing.T) {
+	store := useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "test tip 1", CreatedAt: time.Now()},
+		Tip{ID: "2", Topic: "vim", Content: "test tip 2", CreatedAt: time.Now()},
+	)
+
+	m := testModel(t, []string{}, 60)
+	m.currentTip = m.tipsData.findTip("1")
+	m.showNewTip = false
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("3")})
+	updated := updatedModel.(model)
+
+	if updated.currentTip == nil || updated.currentTip.ID != "2" {
+		t.Errorf("Expected the unreviewed tip 2 next, got %+v", updated.currentTip)
+	}
+
+	stored, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	tip := stored.findTip("1")
+	if tip == nil || tip.Reviews != 1 || tip.IntervalDays != 1 || tip.DueAt == nil {
+		t.Errorf("Expected stored tip 1 to be scheduled in 1 day, got %+v", tip)
+	}
+}
+
+func TestModelShuffle(t *testing.T) {
+	homeDir := t.TempDir()
+	setTestHome(t, homeDir)
+	originalShuffle := shuffleFlag
+	shuffleFlag = true
+	defer func() { shuffleFlag = originalShuffle }()
+
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "test tip 1", CreatedAt: time.Now()},
+		Tip{ID: "2", Topic: "git", Content: "test tip 2", CreatedAt: time.Now()},
+		Tip{ID: "3", Topic: "git", Content: "test tip 3", CreatedAt: time.Now()},
+	)
+
+	m := testModel(t, []string{}, 60)
+	if _, ok := m.strategy.(*shuffleState); !ok || m.currentTip == nil {
+		t.Fatalf("Expected a shuffled first tip, got %+v", m.currentTip)
+	}
+	seen := []string{m.currentTip.ID}
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	seen = append(seen, updatedModel.(model).currentTip.ID)
+
+	// A new session carries on with the same rotation.
+	m = testModel(t, []string{}, 60)
+	seen = append(seen, m.currentTip.ID)
+	if len(newIDSet(seen)) != 3 {
+		t.Errorf("Expected each tip once, got %v", seen)
+	}
+}
+
+func TestModelRateTip(t *testing.T) {
+	store := useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "test tip 1", CreatedAt: time.Now()},
+	)
+
+	var updated tea.Model = testModel(t, []string{}, 60)
+	for i := 0; i < maxRating+2; i++ {
+		updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("+")})
+	}
+	updated, _ = updated.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("-")})
+
+	if msg := updated.(model).message; msg != fmt.Sprintf("Rating: %+d", maxRating-1) {
+		t.Errorf("Expected the rating message, got '%s'", msg)
+	}
+
+	stored, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	if tip := stored.findTip("1"); tip == nil || tip.Rating != maxRating-1 {
+		t.Errorf("Expected stored rating %d, got %+v", maxRating-1, tip)
+	}
+}
+
+func TestModelHistory(t *testing.T) {
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "test tip 1", CreatedAt: time.Now()},
+		Tip{ID: "2", Topic: "git", Content: "test tip 2", CreatedAt: time.Now()},
+	)
+
+	var updated tea.Model = testModel(t, []string{}, 60)
+	key := func(msg tea.KeyMsg) string {
+		updated, _ = updated.Update(msg)
+		return updated.(model).currentTip.ID
+	}
+	next := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")}
+	back := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")}
+
+	shown := []string{updated.(model).currentTip.ID, key(next), key(next)}
+
+	if id := key(back); id != shown[1] {
+		t.Errorf("Expected to go back to %s, got %s", shown[1], id)
+	}
+	if id := key(tea.KeyMsg{Type: tea.KeyLeft}); id != shown[0] {
+		t.Errorf("Expected to go back to %s, got %s", shown[0], id)
+	}
+	if id := key(back); id != shown[0] || updated.(model).message != "No earlier tips" {
+		t.Errorf("Expected to stay on %s at the start of the history, got %s", shown[0], id)
+	}
+	if id := key(tea.KeyMsg{Type: tea.KeyRight}); id != shown[1] {
+		t.Errorf("Expected to go forward to %s, got %s", shown[1], id)
 	}
 
-	for _, tip := range updated.tipsData.Tips {
-		if tip.ID == "1" {
-			t.Error("Tip with ID '1' should have been removed")
+	// Browsing drops a pending refresh instead of jumping to a new tip.
+	updated, _ = updated.Update(tickMsg(time.Now()))
+	if id := key(back); id != shown[0] {
+		t.Errorf("Expected to go back to %s after a refresh, got %s", shown[0], id)
+	}
+
+	// New tips go to the end of the history, even when browsing back.
+	key(next)
+	if history := updated.(model).history; len(history) != 4 {
+		t.Errorf("Expected 4 tips in the history, got %v", history)
+	}
+	key(tea.KeyMsg{Type: tea.KeyRight})
+	if msg := updated.(model).message; msg != "No later tips" {
+		t.Errorf("Expected to be at the end of the history, got '%s'", msg)
+	}
+}
+
+func TestModelSearch(t *testing.T) {
+	setTestHome(t, t.TempDir())
+	known := time.Now()
+	useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "git rebase -i squashes commits", CreatedAt: time.Now()},
+		Tip{ID: "2", Topic: "git", Content: "git stash pop", CreatedAt: time.Now()},
+		Tip{ID: "3", Topic: "git", Content: "git rebase --onto moves a branch", CreatedAt: time.Now()},
+		Tip{ID: "4", Topic: "git", Content: "git rebase --abort", CreatedAt: time.Now(), KnownAt: &known},
+	)
+
+	var updated tea.Model = testModel(t, []string{}, 60)
+	key := func(msgs ...tea.KeyMsg) model {
+		for _, msg := range msgs {
+			updated, _ = updated.Update(msg)
 		}
+		return updated.(model)
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	m := key(runes("/"), runes("rebase"), tea.KeyMsg{Type: tea.KeySpace}, runes("squash"), tea.KeyMsg{Type: tea.KeyBackspace})
+	if !m.searching || m.searchInput != "rebase squas" {
+		t.Fatalf("Expected to be typing a query, got searching=%v input=%q", m.searching, m.searchInput)
+	}
+
+	m = key(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.searching || m.currentTip.ID != "1" || m.message != `Match 1 of 2 for "rebase squas"` {
+		t.Fatalf("Expected the best match first, got %s with message '%s'", m.currentTip.ID, m.message)
+	}
+	if view := m.View(); !strings.Contains(view, "rebase") {
+		t.Errorf("Expected the match in the view, got '%s'", view)
+	}
+
+	m = key(runes("n"))
+	if m.currentTip.ID != "3" || m.message != `Match 2 of 2 for "rebase squas"` {
+		t.Errorf("Expected the second match, got %s with message '%s'", m.currentTip.ID, m.message)
+	}
+
+	m = key(runes("n"))
+	if len(m.searchTerms) != 0 || m.message != "" {
+		t.Errorf("Expected the search to end after the last match, got terms %v and message '%s'", m.searchTerms, m.message)
+	}
+
+	m = key(runes("/"), runes("kubernetes"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.message != `No tips match "kubernetes"` {
+		t.Errorf("Expected no matches, got '%s'", m.message)
+	}
+
+	m = key(runes("/"), runes("q"), tea.KeyMsg{Type: tea.KeyEsc})
+	if m.searching || m.quit {
+		t.Errorf("Expected escape to cancel the search without quitting")
 	}
 }
 
@@ -341,7 +593,9 @@ func (e *TestError) Error() string {
 }
 
 func TestModelStateTransitions(t *testing.T) {
-	m := initialModel([]string{}, 60)
+	useMemoryStore(t)
+
+	m := testModel(t, []string{}, 60)
 
 	expectedShowNewTip := true
 	if m.tipsData != nil && len(m.tipsData.Tips) > 0 && m.currentTip != nil {
@@ -359,7 +613,7 @@ func TestModelStateTransitions(t *testing.T) {
 	}
 
 	if m.showNewTip && m.tipsData != nil {
-		if newTip := m.tipsData.getRandomTip(m.topicFilter); newTip != nil {
+		if newTip, _ := m.strategy.pick(m.tipsData, m.filter); newTip != nil {
 			m.currentTip = newTip
 		}
 		m.showNewTip = false
@@ -374,3 +628,32 @@ func TestModelStateTransitions(t *testing.T) {
 		t.Error("currentTip should be set")
 	}
 }
+
+func TestModelUndoKnown(t *testing.T) {
+	store := useMemoryStore(t,
+		Tip{ID: "1", Topic: "git", Content: "test tip 1", CreatedAt: time.Now()},
+	)
+
+	m := testModel(t, []string{}, 60)
+
+	updatedModel, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	if updated := updatedModel.(model); updated.message != "Nothing to undo" {
+		t.Errorf("Expected nothing to undo, got '%s'", updated.message)
+	}
+
+	updatedModel, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("k")})
+	updatedModel, _ = updatedModel.(model).Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("u")})
+	updated := updatedModel.(model)
+
+	if updated.currentTip == nil || updated.currentTip.ID != "1" || updated.currentTip.isKnown() {
+		t.Errorf("Expected tip 1 to be restored and shown, got %+v", updated.currentTip)
+	}
+
+	stored, err := store.Load()
+	if err != nil {
+		t.Fatalf("Failed to load store: %v", err)
+	}
+	if tip := stored.findTip("1"); tip == nil || tip.isKnown() {
+		t.Errorf("Expected stored tip 1 to be restored, got %+v", tip)
+	}
+}
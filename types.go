@@ -1,95 +1,186 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"math/rand"
-	"os"
-	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
 type Tip struct {
-	ID        string    `json:"id"`
-	Topic     string    `json:"topic"`
-	Content   string    `json:"content"`
-	CreatedAt time.Time `json:"created_at"`
+	ID        string     `json:"id"`
+	Topic     string     `json:"topic"`
+	Content   string     `json:"content"`
+	CreatedAt time.Time  `json:"created_at"`
+	KnownAt   *time.Time `json:"known_at,omitempty"`
+
+	Ease         float64    `json:"ease,omitempty"`
+	IntervalDays int        `json:"interval_days,omitempty"`
+	DueAt        *time.Time `json:"due_at,omitempty"`
+	Reviews      int        `json:"reviews,omitempty"`
+
+	Rating int `json:"rating,omitempty"`
+
+	Tags []string `json:"tags,omitempty"`
+
+	Source string `json:"source,omitempty"`
 }
 
+// Tip sources: generated by a model, or written with 'tips add'.
+const (
+	sourceLLM    = "llm"
+	sourceManual = "manual"
+)
+
+// minIDPrefix is the shortest ID prefix accepted in place of a full tip ID.
+const minIDPrefix = 4
+
+// maxTipLength is the longest tip content addTip accepts, in characters.
+const maxTipLength = 1000
+
 type TipsData struct {
-	Tips []Tip `json:"tips"`
+	SchemaVersion int   `json:"schema_version"`
+	Tips          []Tip `json:"tips"`
+
+	loaded map[string]Tip
+
+	// tipKeys indexes tips by tipKey for duplicate detection. It is built on
+	// the first addTip and rebuilt whenever Tips has changed length behind
+	// its back.
+	tipKeys     map[string]string
+	tipKeysSize int
+}
+
+type duplicateTipError struct {
+	id string
 }
 
-func getTipsFilePath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+func (e *duplicateTipError) Error() string {
+	return fmt.Sprintf("tip already exists (%s)", e.id)
+}
+
+func loadTips() (*TipsData, error) {
+	store, err := openStore()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return filepath.Join(homeDir, ".tips.json"), nil
+	defer store.Close()
+	return store.Load()
 }
 
-func loadTips() (*TipsData, error) {
-	filePath, err := getTipsFilePath()
+// loadTipsByTopic loads only the tips in topics and their subtopics, or every
+// tip if topics is empty.
+func loadTipsByTopic(topics []string) (*TipsData, error) {
+	store, err := openStore()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get tips file path: %w", err)
+		return nil, err
 	}
+	defer store.Close()
+	return loadByTopic(store, topics)
+}
 
-	if _, err := os.Stat(filePath); os.IsNotExist(err) {
-		return &TipsData{}, nil
+// loadByTopic narrows tips down to topics in the store, which the SQLite
+// store does with its topic index rather than reading every tip.
+func loadByTopic(store TipStore, topics []string) (*TipsData, error) {
+	if len(topics) == 0 {
+		return store.Load()
+	}
+	tips, err := store.QueryByTopic(topics)
+	if err != nil {
+		return nil, err
 	}
+	tipsData := &TipsData{Tips: tips}
+	tipsData.markLoaded()
+	return tipsData, nil
+}
 
-	data, err := os.ReadFile(filePath)
+func saveTips(tipsData *TipsData) error {
+	store, err := openStore()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read tips file: %w", err)
+		return err
 	}
+	defer store.Close()
+	return store.Save(tipsData)
+}
 
-	if len(data) == 0 {
-		return &TipsData{}, nil
+// addTip validates and adds a tip, returning a pointer to it in td.Tips.
+// A tip with the same topic and content as an existing one, ignoring case
+// and whitespace, is rejected with a *duplicateTipError.
+func (td *TipsData) addTip(topic, content string, tags ...string) (*Tip, error) {
+	topic, content, err := validateTip(topic, content)
+	if err != nil {
+		return nil, err
 	}
 
-	var tipsData TipsData
-	if err := json.Unmarshal(data, &tipsData); err != nil {
-		return nil, fmt.Errorf("failed to parse tips file: %w", err)
+	if td.tipKeys == nil || td.tipKeysSize != len(td.Tips) {
+		td.tipKeys = make(map[string]string, len(td.Tips))
+		for _, tip := range td.Tips {
+			td.tipKeys[tipKey(tip.Topic, tip.Content)] = tip.ID
+		}
+	}
+	key := tipKey(topic, content)
+	if id, exists := td.tipKeys[key]; exists {
+		return nil, &duplicateTipError{id: id}
 	}
 
-	return &tipsData, nil
+	td.Tips = append(td.Tips, Tip{
+		ID:        uuid.New().String(),
+		Topic:     topic,
+		Content:   content,
+		CreatedAt: time.Now(),
+		Tags:      normalizeTags(tags),
+	})
+	tip := &td.Tips[len(td.Tips)-1]
+	td.tipKeys[key] = tip.ID
+	td.tipKeysSize = len(td.Tips)
+	return tip, nil
 }
 
-func saveTips(tipsData *TipsData) error {
-	if tipsData == nil {
-		return fmt.Errorf("tips data cannot be nil")
+// editTip validates new contents for the tip with id and applies them,
+// rejecting duplicates of any other tip like addTip.
+func (td *TipsData) editTip(id, topic, content string, tags []string) (*Tip, error) {
+	tip := td.findTip(id)
+	if tip == nil {
+		return nil, fmt.Errorf("tip %s not found", id)
 	}
 
-	filePath, err := getTipsFilePath()
+	topic, content, err := validateTip(topic, content)
 	if err != nil {
-		return fmt.Errorf("failed to get tips file path: %w", err)
+		return nil, err
+	}
+	key := tipKey(topic, content)
+	for _, other := range td.Tips {
+		if other.ID != id && tipKey(other.Topic, other.Content) == key {
+			return nil, &duplicateTipError{id: other.ID}
+		}
 	}
 
-	data, err := json.MarshalIndent(tipsData, "", "  ")
+	tip.Topic, tip.Content, tip.Tags = topic, content, normalizeTags(tags)
+	td.tipKeys = nil
+	return tip, nil
+}
+
+// validateTip returns the tidied topic and content of a new or edited tip.
+func validateTip(topic, content string) (string, string, error) {
+	topic, err := canonicalTopic(topic)
 	if err != nil {
-		return fmt.Errorf("failed to marshal tips data: %w", err)
+		return "", "", err
 	}
-
-	if err := os.WriteFile(filePath, data, 0644); err != nil {
-		return fmt.Errorf("failed to write tips file: %w", err)
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return "", "", fmt.Errorf("tip content is empty")
 	}
-
-	return nil
+	if n := utf8.RuneCountInString(content); n > maxTipLength {
+		return "", "", fmt.Errorf("tip content is %d characters long, the limit is %d", n, maxTipLength)
+	}
+	return topic, content, nil
 }
 
-func (td *TipsData) addTip(topic, content string) {
-	if topic == "" || content == "" {
-		return
-	}
-	td.Tips = append(td.Tips, Tip{
-		ID:        uuid.New().String(),
-		Topic:     strings.TrimSpace(topic),
-		Content:   strings.TrimSpace(content),
-		CreatedAt: time.Now(),
-	})
+func tipKey(topic, content string) string {
+	return strings.ToLower(topic) + "\x00" + strings.Join(strings.Fields(strings.ToLower(content)), " ")
 }
 
 func (td *TipsData) removeTip(id string) bool {
@@ -102,29 +193,171 @@ func (td *TipsData) removeTip(id string) bool {
 	return false
 }
 
-func (td *TipsData) getRandomTip(topics []string) *Tip {
-	if len(td.Tips) == 0 {
-		return nil
+func (td *TipsData) findTip(id string) *Tip {
+	for i := range td.Tips {
+		if td.Tips[i].ID == id {
+			return &td.Tips[i]
+		}
+	}
+	return nil
+}
+
+// resolveTip finds a tip by its ID or, like git, a unique prefix of it at
+// least minIDPrefix characters long.
+func (td *TipsData) resolveTip(ref string) (*Tip, error) {
+	ref = strings.TrimSpace(ref)
+	if tip := td.findTip(ref); tip != nil {
+		return tip, nil
+	}
+	if len(ref) < minIDPrefix {
+		return nil, fmt.Errorf("tip %s not found (ID prefixes need at least %d characters)", ref, minIDPrefix)
 	}
 
-	filteredTips := td.Tips
-	if len(topics) > 0 {
-		topicSet := make(map[string]struct{}, len(topics))
-		for _, topic := range topics {
-			topicSet[topic] = struct{}{}
+	var matches []*Tip
+	for i := range td.Tips {
+		if len(td.Tips[i].ID) >= len(ref) && strings.EqualFold(td.Tips[i].ID[:len(ref)], ref) {
+			matches = append(matches, &td.Tips[i])
 		}
+	}
 
-		filteredTips = make([]Tip, 0, len(td.Tips))
-		for _, tip := range td.Tips {
-			if _, exists := topicSet[tip.Topic]; exists {
-				filteredTips = append(filteredTips, tip)
-			}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("tip %s not found", ref)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, tip := range matches {
+			ids[i] = tip.ID
+		}
+		return nil, fmt.Errorf("tip ID prefix %s is ambiguous, it matches: %s", ref, strings.Join(ids, ", "))
+	}
+}
+
+// resolveTipIDs resolves every ref with resolveTip, returning the full IDs.
+func (td *TipsData) resolveTipIDs(refs []string) ([]string, error) {
+	ids := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		tip, err := td.resolveTip(ref)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, tip.ID)
+	}
+	return ids, nil
+}
+
+func (tip *Tip) isKnown() bool {
+	return tip.KnownAt != nil
+}
+
+// newTipFilter matches the tips that can be shown: not known, and passing
+// filter.
+func newTipFilter(filter tipFilter) func(tip *Tip) bool {
+	matches := filter.matcher()
+	return func(tip *Tip) bool {
+		return !tip.isKnown() && matches(tip)
+	}
+}
+
+func (td *TipsData) filterByTopic(topics []string) []Tip {
+	if len(topics) == 0 {
+		return td.Tips
+	}
+
+	matches := tipFilter{topics: topics}.matcher()
+	filteredTips := make([]Tip, 0, len(td.Tips))
+	for _, tip := range td.Tips {
+		if matches(&tip) {
+			filteredTips = append(filteredTips, tip)
 		}
 	}
+	return filteredTips
+}
 
-	if len(filteredTips) == 0 {
-		return nil
+func (td *TipsData) markLoaded() {
+	td.loaded = make(map[string]Tip, len(td.Tips))
+	for _, tip := range td.Tips {
+		td.loaded[tip.ID] = tip
 	}
+}
 
-	return &filteredTips[rand.Intn(len(filteredTips))]
+// delta reports the tips added, changed and removed since td was loaded from
+// a store.
+func (td *TipsData) delta() (added, updated []Tip, removed []string) {
+	kept := make(map[string]struct{}, len(td.Tips))
+	for _, tip := range td.Tips {
+		kept[tip.ID] = struct{}{}
+		original, wasLoaded := td.loaded[tip.ID]
+		if !wasLoaded {
+			added = append(added, tip)
+		} else if !reflect.DeepEqual(original, tip) {
+			updated = append(updated, tip)
+		}
+	}
+
+	for id := range td.loaded {
+		if _, isKept := kept[id]; !isKept {
+			removed = append(removed, id)
+		}
+	}
+	return added, updated, removed
+}
+
+// mergeInto applies td's delta on top of current, so concurrent writers don't
+// clobber each other. Data that was not loaded from a store replaces current
+// entirely.
+func (td *TipsData) mergeInto(current *TipsData) *TipsData {
+	if td.loaded == nil {
+		return &TipsData{Tips: append([]Tip(nil), td.Tips...)}
+	}
+
+	added, updated, removed := td.delta()
+	removedSet := newIDSet(removed)
+	updatedByID := make(map[string]Tip, len(updated))
+	for _, tip := range updated {
+		updatedByID[tip.ID] = tip
+	}
+
+	merged := &TipsData{Tips: make([]Tip, 0, len(current.Tips)+len(added))}
+	present := make(map[string]struct{}, len(current.Tips))
+	for _, tip := range current.Tips {
+		if _, isRemoved := removedSet[tip.ID]; isRemoved {
+			continue
+		}
+		if updatedTip, isUpdated := updatedByID[tip.ID]; isUpdated {
+			tip = updatedTip
+		}
+		merged.Tips = append(merged.Tips, tip)
+		present[tip.ID] = struct{}{}
+	}
+
+	for _, tip := range added {
+		if _, exists := present[tip.ID]; !exists {
+			merged.Tips = append(merged.Tips, tip)
+		}
+	}
+
+	return merged
+}
+
+// updateTips replaces the tips with matching IDs and reports how many were
+// found.
+func (td *TipsData) updateTips(tips []Tip) int {
+	updated := 0
+	for _, tip := range tips {
+		if existing := td.findTip(tip.ID); existing != nil {
+			*existing = tip
+			updated++
+		}
+	}
+	return updated
+}
+
+func newIDSet(ids []string) map[string]struct{} {
+	idSet := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		idSet[id] = struct{}{}
+	}
+	return idSet
 }
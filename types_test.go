@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -44,15 +45,15 @@ func TestTipsData_addTip(t *testing.T) {
 			name:        "whitespace topic",
 			topic:       "  ",
 			content:     "Some content",
-			expectAdded: true,
-			expectedLen: 1,
+			expectAdded: false,
+			expectedLen: 0,
 		},
 		{
 			name:        "whitespace content",
 			topic:       "git",
 			content:     "  ",
-			expectAdded: true,
-			expectedLen: 1,
+			expectAdded: false,
+			expectedLen: 0,
 		},
 		{
 			name:        "topic with whitespace",
@@ -97,6 +98,48 @@ func TestTipsData_addTip(t *testing.T) {
 	}
 }
 
+func TestTipsData_addTip_Validation(t *testing.T) {
+	td := &TipsData{Tips: []Tip{{ID: "1", Topic: "Git", Content: "git  stash pop"}}}
+
+	tests := []struct {
+		name          string
+		topic         string
+		content       string
+		expectedTopic string
+		expectError   bool
+	}{
+		{name: "duplicate ignoring case and whitespace", topic: "git", content: "Git stash\tpop ", expectError: true},
+		{name: "same content in another topic", topic: "vim", content: "git stash pop", expectedTopic: "vim"},
+		{name: "topic path tidied", topic: " /go / testing/ ", content: "t.Run runs subtests", expectedTopic: "go/testing"},
+		{name: "empty path segment", topic: "go//testing", content: "t.Helper marks helpers", expectError: true},
+		{name: "wildcard in topic", topic: "go/*", content: "go vet finds bugs", expectError: true},
+		{name: "too long", topic: "git", content: strings.Repeat("x", maxTipLength+1), expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tip, err := td.addTip(tt.topic, tt.content)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got tip %+v", tip)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("addTip failed: %v", err)
+			}
+			if tip.Topic != tt.expectedTopic || td.findTip(tip.ID) == nil {
+				t.Errorf("Expected a stored tip in %q, got %+v", tt.expectedTopic, tip)
+			}
+		})
+	}
+
+	var duplicate *duplicateTipError
+	if _, err := td.addTip("go/testing", "T.Run runs subtests"); !errors.As(err, &duplicate) || duplicate.id != td.Tips[2].ID {
+		t.Errorf("Expected a duplicate of the tip added above, got %v", err)
+	}
+}
+
 func TestTipsData_removeTip(t *testing.T) {
 	td := &TipsData{}
 
@@ -158,11 +201,11 @@ func TestTipsData_removeTip(t *testing.T) {
 	}
 }
 
-func TestTipsData_getRandomTip(t *testing.T) {
+func TestSRSStrategy_pick(t *testing.T) {
 	td := &TipsData{}
 
 	// Test empty tips
-	tip := td.getRandomTip(nil)
+	tip, _ := srsStrategy{}.pick(td, tipFilter{})
 	if tip != nil {
 		t.Error("Expected nil tip for empty tips data")
 	}
@@ -214,7 +257,7 @@ func TestTipsData_getRandomTip(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tip := td.getRandomTip(tt.topics)
+			tip, _ := srsStrategy{}.pick(td, tipFilter{topics: tt.topics})
 
 			if !tt.shouldFind {
 				if tip != nil {
@@ -242,31 +285,53 @@ func TestTipsData_getRandomTip(t *testing.T) {
 			}
 		})
 	}
-}
-
-func TestGetTipsFilePath(t *testing.T) {
-	path, err := getTipsFilePath()
-	if err != nil {
-		t.Fatalf("getTipsFilePath failed: %v", err)
-	}
 
-	if !strings.HasSuffix(path, ".tips.json") {
-		t.Errorf("Expected path to end with .tips.json, got %s", path)
-	}
+	t.Run("skips known tips", func(t *testing.T) {
+		knownAt := time.Now()
+		td := &TipsData{Tips: []Tip{
+			{ID: "1", Topic: "git", Content: "known git tip", KnownAt: &knownAt},
+			{ID: "2", Topic: "git", Content: "git tip"},
+			{ID: "3", Topic: "vim", Content: "known vim tip", KnownAt: &knownAt},
+		}}
+
+		for i := 0; i < 20; i++ {
+			if tip, _ := (srsStrategy{}).pick(td, tipFilter{}); tip == nil || tip.ID != "2" {
+				t.Fatalf("Expected only unknown tip 2, got %+v", tip)
+			}
+		}
+		if tip, _ := (srsStrategy{}).pick(td, tipFilter{topics: []string{"vim"}}); tip != nil {
+			t.Errorf("Expected no tip when all vim tips are known, got %+v", tip)
+		}
+	})
+	t.Run("prefers overdue then new tips", func(t *testing.T) {
+		past := time.Now().Add(-time.Hour)
+		future := time.Now().Add(24 * time.Hour)
+		td := &TipsData{Tips: []Tip{
+			{ID: "1", Topic: "git", Content: "scheduled", DueAt: &future},
+			{ID: "2", Topic: "git", Content: "new"},
+			{ID: "3", Topic: "vim", Content: "overdue", DueAt: &past},
+		}}
+
+		for i := 0; i < 20; i++ {
+			if tip, _ := (srsStrategy{}).pick(td, tipFilter{}); tip == nil || tip.ID != "3" {
+				t.Fatalf("Expected overdue tip 3, got %+v", tip)
+			}
+			if tip, _ := (srsStrategy{}).pick(td, tipFilter{topics: []string{"git"}}); tip == nil || tip.ID != "2" {
+				t.Fatalf("Expected new tip 2, got %+v", tip)
+			}
+		}
 
-	if !filepath.IsAbs(path) {
-		t.Errorf("Expected absolute path, got %s", path)
-	}
+		td.Tips = td.Tips[:1]
+		if tip, _ := (srsStrategy{}).pick(td, tipFilter{}); tip == nil || tip.ID != "1" {
+			t.Errorf("Expected scheduled tip 1 when nothing else is left, got %+v", tip)
+		}
+	})
 }
 
 func TestLoadTips(t *testing.T) {
 	// Test with non-existent file
 	t.Run("non-existent file", func(t *testing.T) {
-		// Temporarily change home directory to avoid conflicts
-		originalHome := os.Getenv("HOME")
-		tmpDir := t.TempDir()
-		os.Setenv("HOME", tmpDir)
-		defer os.Setenv("HOME", originalHome)
+		useStore(t, newJSONFileStore(filepath.Join(t.TempDir(), ".tips.json")))
 
 		tipsData, err := loadTips()
 		if err != nil {
@@ -292,10 +357,7 @@ func TestLoadTips(t *testing.T) {
 			t.Fatalf("Failed to create empty file: %v", err)
 		}
 
-		// Mock getTipsFilePath
-		originalHome := os.Getenv("HOME")
-		os.Setenv("HOME", tmpDir)
-		defer os.Setenv("HOME", originalHome)
+		useStore(t, newJSONFileStore(filePath))
 
 		tipsData, err := loadTips()
 		if err != nil {
@@ -328,10 +390,7 @@ func TestLoadTips(t *testing.T) {
 			t.Fatalf("Failed to write test file: %v", err)
 		}
 
-		// Mock getTipsFilePath
-		originalHome := os.Getenv("HOME")
-		os.Setenv("HOME", tmpDir)
-		defer os.Setenv("HOME", originalHome)
+		useStore(t, newJSONFileStore(filePath))
 
 		tipsData, err := loadTips()
 		if err != nil {
@@ -351,6 +410,8 @@ func TestLoadTips(t *testing.T) {
 func TestSaveTips(t *testing.T) {
 	// Test with nil data
 	t.Run("nil data", func(t *testing.T) {
+		useMemoryStore(t)
+
 		err := saveTips(nil)
 		if err == nil {
 			t.Error("Expected error for nil tips data")
@@ -360,11 +421,8 @@ func TestSaveTips(t *testing.T) {
 	// Test with valid data
 	t.Run("valid data", func(t *testing.T) {
 		tmpDir := t.TempDir()
-
-		// Mock getTipsFilePath
-		originalHome := os.Getenv("HOME")
-		os.Setenv("HOME", tmpDir)
-		defer os.Setenv("HOME", originalHome)
+		filePath := filepath.Join(tmpDir, ".tips.json")
+		useStore(t, newJSONFileStore(filePath))
 
 		testData := &TipsData{
 			Tips: []Tip{
@@ -378,7 +436,6 @@ func TestSaveTips(t *testing.T) {
 		}
 
 		// Verify file was created
-		filePath := filepath.Join(tmpDir, ".tips.json")
 		if _, err := os.Stat(filePath); os.IsNotExist(err) {
 			t.Error("Tips file was not created")
 		}
@@ -399,3 +456,68 @@ func TestSaveTips(t *testing.T) {
 		}
 	})
 }
+
+func TestTipsData_mergeInto(t *testing.T) {
+	now := time.Now()
+	tip := func(id string) Tip {
+		return Tip{ID: id, Topic: "git", Content: "tip " + id, CreatedAt: now}
+	}
+
+	t.Run("applies local delta to current state", func(t *testing.T) {
+		local := &TipsData{Tips: []Tip{tip("1"), tip("2")}}
+		local.markLoaded()
+
+		local.removeTip("1")
+		local.Tips = append(local.Tips, tip("3"))
+
+		// Meanwhile another writer added tip 4.
+		current := &TipsData{Tips: []Tip{tip("1"), tip("2"), tip("4")}}
+
+		merged := local.mergeInto(current)
+
+		var ids []string
+		for _, tip := range merged.Tips {
+			ids = append(ids, tip.ID)
+		}
+		if strings.Join(ids, ",") != "2,4,3" {
+			t.Errorf("Expected merged tips 2,4,3, got %v", ids)
+		}
+	})
+
+	t.Run("does not resurrect tips removed elsewhere", func(t *testing.T) {
+		local := &TipsData{Tips: []Tip{tip("1"), tip("2")}}
+		local.markLoaded()
+
+		current := &TipsData{Tips: []Tip{tip("2")}}
+
+		merged := local.mergeInto(current)
+		if len(merged.Tips) != 1 || merged.Tips[0].ID != "2" {
+			t.Errorf("Expected only tip 2, got %+v", merged.Tips)
+		}
+	})
+
+	t.Run("applies local updates", func(t *testing.T) {
+		local := &TipsData{Tips: []Tip{tip("1"), tip("2")}}
+		local.markLoaded()
+
+		knownAt := now
+		local.findTip("1").KnownAt = &knownAt
+
+		current := &TipsData{Tips: []Tip{tip("1"), tip("2"), tip("3")}}
+
+		merged := local.mergeInto(current)
+		if len(merged.Tips) != 3 || !merged.Tips[0].isKnown() || merged.Tips[1].isKnown() {
+			t.Errorf("Expected tip 1 known among 3 tips, got %+v", merged.Tips)
+		}
+	})
+
+	t.Run("unloaded data replaces current state", func(t *testing.T) {
+		local := &TipsData{Tips: []Tip{tip("1")}}
+		current := &TipsData{Tips: []Tip{tip("2"), tip("3")}}
+
+		merged := local.mergeInto(current)
+		if len(merged.Tips) != 1 || merged.Tips[0].ID != "1" {
+			t.Errorf("Expected only tip 1, got %+v", merged.Tips)
+		}
+	})
+}